@@ -0,0 +1,272 @@
+package smis
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// TagPath is the struct tag containing the name of the path variable
+	TagPath = "path"
+
+	// TagQuery is the struct tag containing the name of the query parameter
+	TagQuery = "query"
+
+	// TagHeader is the struct tag containing the name of the header
+	TagHeader = "header"
+
+	// TagDefault is the struct tag containing the value used if the parameter is missing
+	TagDefault = "default"
+
+	// TagLayout is the struct tag containing the layout to parse time.Time, default is time.RFC3339
+	TagLayout = "layout"
+)
+
+// nolint: gochecknoglobals
+var (
+	typeDuration        = reflect.TypeOf(time.Duration(0))
+	typeTime            = reflect.TypeOf(time.Time{})
+	typeTextUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+type parameterSource struct {
+	tag    string
+	values func(request *http.Request, name string) []string
+}
+
+// nolint: gochecknoglobals
+var parameterSources = []parameterSource{
+	{
+		tag: TagPath,
+		values: func(request *http.Request, name string) []string {
+			if v, ok := mux.Vars(request)[name]; ok {
+				return []string{v}
+			}
+
+			return nil
+		},
+	},
+	{
+		tag: TagQuery,
+		values: func(request *http.Request, name string) []string {
+			return request.URL.Query()[name]
+		},
+	},
+	{
+		tag: TagHeader,
+		values: func(request *http.Request, name string) []string {
+			return request.Header.Values(name)
+		},
+	},
+}
+
+// Bind fills the struct dst points to from path variables, query parameters and headers of the request, e.g.
+// `path:"id"`, `query:"page" default:"1"` or `header:"X-Tenant"`. Supported are strings, ints, uints, floats, bools,
+// time.Duration, time.Time (see TagLayout), encoding.TextUnmarshaler and slices or pointers of those. Slices take
+// repeated and comma separated values. After binding the struct is validated, see ValidateStruct(). All conversion and
+// validation errors are returned at once as *RequestError with status 400.
+func (r *Request) Bind(request *http.Request, dst interface{}) error {
+	value := reflect.ValueOf(dst)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind target must be a pointer to a struct but is %T", dst)
+	}
+
+	var fields []FieldError
+	if err := bindStruct(request, value.Elem(), &fields); err != nil {
+		r.logError(fmt.Sprintf("failed to bind request parameters: %v", err))
+		return err
+	}
+
+	if len(fields) > 0 {
+		return &RequestError{Status: http.StatusBadRequest, Detail: "invalid parameters", Fields: fields}
+	}
+
+	fields, err := ValidateStruct(dst)
+	if err != nil {
+		r.logError(fmt.Sprintf("failed to validate request parameters: %v", err))
+		return err
+	}
+
+	if len(fields) > 0 {
+		return &RequestError{Status: http.StatusBadRequest, Detail: "validation failed", Fields: fields}
+	}
+
+	return nil
+}
+
+func bindStruct(request *http.Request, value reflect.Value, fields *[]FieldError) error {
+	valueType := value.Type()
+
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := bindStruct(request, value.Field(i), fields); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		name, values, ok := lookupParameter(request, field)
+		if !ok {
+			continue
+		}
+
+		if len(values) == 0 {
+			def, ok := field.Tag.Lookup(TagDefault)
+			if !ok {
+				continue
+			}
+
+			values = []string{def}
+		}
+
+		if err := bindValue(value.Field(i), values, field.Tag.Get(TagLayout)); err != nil {
+			var typeErr *unsupportedTypeError
+			if errors.As(err, &typeErr) {
+				return fmt.Errorf("field %s: %w", field.Name, err)
+			}
+
+			*fields = append(*fields, FieldError{Field: name, Rule: "type", Message: err.Error()})
+		}
+	}
+
+	return nil
+}
+
+func lookupParameter(request *http.Request, field reflect.StructField) (string, []string, bool) {
+	for _, source := range parameterSources {
+		if name, ok := field.Tag.Lookup(source.tag); ok && name != "" {
+			return name, source.values(request, name), true
+		}
+	}
+
+	return "", nil, false
+}
+
+type unsupportedTypeError struct {
+	t reflect.Type
+}
+
+func (e *unsupportedTypeError) Error() string {
+	return fmt.Sprintf("type %s is not supported", e.t)
+}
+
+func bindValue(value reflect.Value, values []string, layout string) error {
+	if value.Kind() == reflect.Slice && !reflect.PtrTo(value.Type()).Implements(typeTextUnmarshaler) {
+		var items []string
+		for _, v := range values {
+			items = append(items, strings.Split(v, ",")...)
+		}
+
+		slice := reflect.MakeSlice(value.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := parseValue(slice.Index(i), strings.TrimSpace(item), layout); err != nil {
+				return err
+			}
+		}
+
+		value.Set(slice)
+
+		return nil
+	}
+
+	return parseValue(value, values[0], layout)
+}
+
+func parseValue(value reflect.Value, raw, layout string) error { // nolint: gocyclo
+	if value.Kind() == reflect.Ptr {
+		ptr := reflect.New(value.Type().Elem())
+		if err := parseValue(ptr.Elem(), raw, layout); err != nil {
+			return err
+		}
+
+		value.Set(ptr)
+
+		return nil
+	}
+
+	if value.CanAddr() && value.Addr().Type().Implements(typeTextUnmarshaler) && value.Type() != typeTime {
+		if err := value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw)); err != nil {
+			return fmt.Errorf("invalid value '%s': %v", raw, err)
+		}
+
+		return nil
+	}
+
+	typeErr := fmt.Errorf("must be of type %s", value.Type())
+
+	switch value.Type() {
+	case typeDuration:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return typeErr
+		}
+
+		value.SetInt(int64(d))
+
+		return nil
+	case typeTime:
+		if layout == "" {
+			layout = time.RFC3339
+		}
+
+		t, err := time.Parse(layout, raw)
+		if err != nil {
+			return fmt.Errorf("must be a time of layout %s", layout)
+		}
+
+		value.Set(reflect.ValueOf(t))
+
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return typeErr
+		}
+
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return typeErr
+		}
+
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return typeErr
+		}
+
+		value.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return typeErr
+		}
+
+		value.SetFloat(f)
+	default:
+		return &unsupportedTypeError{t: value.Type()}
+	}
+
+	return nil
+}
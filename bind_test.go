@@ -0,0 +1,174 @@
+package smis_test
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis"
+)
+
+type paginationTest struct {
+	Page    int `query:"page" default:"1" validate:"min=1"`
+	PerPage int `query:"per_page" default:"20" validate:"max=100"`
+}
+
+type listParamsTest struct {
+	paginationTest
+	ID       uint64        `path:"id"`
+	Tenant   string        `header:"X-Tenant"`
+	Active   *bool         `query:"active"`
+	Since    time.Time     `query:"since"`
+	Day      time.Time     `query:"day" layout:"2006-01-02"`
+	Timeout  time.Duration `query:"timeout" default:"5s"`
+	Tags     []string      `query:"tag"`
+	Scores   []float64     `query:"score"`
+	IP       net.IP        `query:"ip"`
+	Untagged string
+}
+
+func TestRequest_Bind(t *testing.T) { // nolint: funlen
+	active := true
+
+	testCases := []struct {
+		name           string
+		url            string
+		header         http.Header
+		expected       listParamsTest
+		expectedStatus int
+		expectedFields []smis.FieldError
+	}{
+		{
+			name: "defaults",
+			url:  "/users/42",
+			expected: listParamsTest{
+				paginationTest: paginationTest{Page: 1, PerPage: 20},
+				ID:             42,
+				Timeout:        5 * time.Second,
+			},
+		},
+		{
+			name: "all parameters",
+			url: "/users/42?page=2&per_page=50&active=true&since=2020-01-02T03:04:05Z&day=2020-05-06&timeout=1m" +
+				"&tag=a,b&tag=c&score=1.5&score=2&ip=127.0.0.1",
+			header: http.Header{"X-Tenant": []string{"acme"}},
+			expected: listParamsTest{
+				paginationTest: paginationTest{Page: 2, PerPage: 50},
+				ID:             42,
+				Tenant:         "acme",
+				Active:         &active,
+				Since:          time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+				Day:            time.Date(2020, 5, 6, 0, 0, 0, 0, time.UTC),
+				Timeout:        time.Minute,
+				Tags:           []string{"a", "b", "c"},
+				Scores:         []float64{1.5, 2},
+				IP:             net.ParseIP("127.0.0.1"),
+			},
+		},
+		{
+			name:           "conversion errors",
+			url:            "/users/-1?page=two&active=yes&since=today&day=2020&timeout=1x&score=1,a&ip=localhost",
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []smis.FieldError{
+				{Field: "page", Rule: "type", Message: "must be of type int"},
+				{Field: "id", Rule: "type", Message: "must be of type uint64"},
+				{Field: "active", Rule: "type", Message: "must be of type bool"},
+				{Field: "since", Rule: "type", Message: "must be a time of layout 2006-01-02T15:04:05Z07:00"},
+				{Field: "day", Rule: "type", Message: "must be a time of layout 2006-01-02"},
+				{Field: "timeout", Rule: "type", Message: "must be of type time.Duration"},
+				{Field: "score", Rule: "type", Message: "must be of type float64"},
+				{Field: "ip", Rule: "type", Message: "invalid value 'localhost': invalid IP address: localhost"},
+			},
+		},
+		{
+			name:           "validation fails",
+			url:            "/users/42?page=0&per_page=200",
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []smis.FieldError{
+				{Field: "page", Rule: "min", Message: "must be at least 1"},
+				{Field: "per_page", Rule: "max", Message: "must be at most 100"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, testCase.url, nil)
+			for k, v := range testCase.header {
+				request.Header[k] = v
+			}
+
+			var got listParamsTest
+
+			var err error
+
+			router := mux.NewRouter()
+			router.HandleFunc("/users/{id}", func(_ http.ResponseWriter, request *http.Request) {
+				err = (&smis.Request{}).Bind(request, &got)
+			})
+			router.ServeHTTP(httptest.NewRecorder(), request)
+
+			if testCase.expectedStatus == 0 {
+				if err != nil {
+					t.Fatalf("expected no error but got: %s", err)
+				}
+
+				if !reflect.DeepEqual(testCase.expected, got) {
+					t.Errorf("expected %+v but got %+v", testCase.expected, got)
+				}
+
+				return
+			}
+
+			var requestErr *smis.RequestError
+			if !errors.As(err, &requestErr) {
+				t.Fatalf("expected a request error but got: %v", err)
+			}
+
+			if testCase.expectedStatus != requestErr.Status {
+				t.Errorf("expected status %d but got %d", testCase.expectedStatus, requestErr.Status)
+			}
+
+			if !reflect.DeepEqual(testCase.expectedFields, requestErr.Fields) {
+				t.Errorf("expected field errors\n%v\nbut got\n%v", testCase.expectedFields, requestErr.Fields)
+			}
+		})
+	}
+}
+
+func TestRequest_Bind_InvalidTarget(t *testing.T) {
+	testCases := []struct {
+		name string
+		dst  interface{}
+	}{
+		{
+			name: "no pointer",
+			dst:  paginationTest{},
+		},
+		{
+			name: "no struct",
+			dst:  new(string),
+		},
+		{
+			name: "unsupported type",
+			dst: &struct {
+				Filter map[string]string `query:"filter"`
+			}{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/?filter=a", nil)
+			if err := (&smis.Request{}).Bind(request, testCase.dst); err == nil {
+				t.Error("expected an error but got nil")
+			}
+		})
+	}
+}
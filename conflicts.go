@@ -0,0 +1,233 @@
+package smis
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// ErrRouteConflict is wrapped by all errors returned by Validate()
+var ErrRouteConflict = errors.New("route conflict")
+
+type routeEntry struct {
+	chain    string
+	template string
+	methods  []string
+	regex    *regexp.Regexp
+}
+
+// Validate checks the registered routes for conflicts: routes registered twice for the same method and path, routes
+// of the default chain colliding with the prefix of another chain and routes never matched because a route matched
+// before covers them, e.g. a file server. Routes with host or query matchers are not checked. It returns all conflicts
+// at once, each wrapping ErrRouteConflict.
+func (s *Service) Validate() error {
+	entries, err := s.routeEntries()
+	if err != nil {
+		return err
+	}
+
+	var errs Errors
+
+	for i, entry := range entries {
+		errs = errs.Append(s.checkChainPrefix(entry))
+
+		for _, before := range entries[:i] {
+			if !methodsOverlap(before.methods, entry.methods) {
+				continue
+			}
+
+			if before.regex.String() == entry.regex.String() {
+				errs = errs.Append(fmt.Errorf(
+					"%w: %s %s is registered twice (chain %s and chain %s), the second registration is never matched",
+					ErrRouteConflict, formatMethods(entry.methods), entry.template, before.chain, entry.chain,
+				))
+
+				break
+			}
+
+			if sample, ok := samplePath(entry); ok && before.regex.MatchString(sample) {
+				errs = errs.Append(fmt.Errorf(
+					"%w: %s %s of chain %s is unreachable, it is shadowed by %s %s of chain %s",
+					ErrRouteConflict, formatMethods(entry.methods), entry.template, entry.chain,
+					formatMethods(before.methods), before.template, before.chain,
+				))
+
+				break
+			}
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// routeEntries collects the routes with handler in the order the router matches them.
+func (s *Service) routeEntries() ([]routeEntry, error) {
+	var entries []routeEntry
+
+	err := s.Router.Walk(func(route *mux.Route, router *mux.Router, _ []*mux.Route) error {
+		if route.GetHandler() == nil {
+			return nil // the route of a sub router
+		}
+
+		if _, err := route.GetHostTemplate(); err == nil {
+			return nil
+		}
+
+		if queries, err := route.GetQueriesTemplates(); err == nil && len(queries) > 0 {
+			return nil
+		}
+
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+
+		pathRegex, err := route.GetPathRegexp()
+		if err != nil {
+			return err
+		}
+
+		regex, err := regexp.Compile(pathRegex)
+		if err != nil {
+			return err
+		}
+
+		methods, _ := route.GetMethods()
+
+		entries = append(entries, routeEntry{
+			chain:    s.chainOfRouter(router),
+			template: template,
+			methods:  methods,
+			regex:    regex,
+		})
+
+		return nil
+	})
+
+	return entries, err
+}
+
+// checkChainPrefix returns an error if a route of the default chain starts with the prefix of another chain.
+func (s *Service) checkChainPrefix(entry routeEntry) error {
+	if entry.chain != MiddlewareChainDefault {
+		return nil
+	}
+
+	chain := s.chainOfTemplate(entry.template)
+	if chain == MiddlewareChainDefault {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"%w: %s %s of chain %s collides with the prefix /%s of chain %s",
+		ErrRouteConflict, formatMethods(entry.methods), entry.template, MiddlewareChainDefault, chain, chain,
+	)
+}
+
+// chainOfTemplate returns the chain whose prefix the path template starts with. Use chainOfRoute() to get the chain
+// owning a route.
+func (s *Service) chainOfTemplate(template string) string {
+	for chain := range s.SubRouters {
+		if template == "/"+chain || strings.HasPrefix(template, "/"+chain+"/") {
+			return chain
+		}
+	}
+
+	return MiddlewareChainDefault
+}
+
+// samplePath returns a path matched by the route of the entry. Variables are replaced by a sample of their pattern,
+// it returns false if no sample could be built.
+func samplePath(entry routeEntry) (string, bool) {
+	var failed bool
+
+	sample := pathVariableRegex.ReplaceAllStringFunc(entry.template, func(variable string) string {
+		pattern := pathVariableRegex.FindStringSubmatch(variable)[2]
+		if pattern == "" {
+			return "_"
+		}
+
+		re, err := syntax.Parse(strings.TrimPrefix(pattern, ":"), syntax.Perl)
+		if err != nil {
+			failed = true
+			return ""
+		}
+
+		return sampleOf(re.Simplify())
+	})
+
+	return sample, !failed && entry.regex.MatchString(sample)
+}
+
+// sampleOf returns a string matched by the regular expression, preferring readable characters. Assertions are
+// ignored, so the caller needs to verify the sample.
+func sampleOf(re *syntax.Regexp) string { // nolint: exhaustive
+	switch re.Op {
+	case syntax.OpLiteral:
+		return string(re.Rune)
+	case syntax.OpCharClass:
+		return string(sampleRune(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return "_"
+	case syntax.OpCapture, syntax.OpPlus:
+		return sampleOf(re.Sub[0])
+	case syntax.OpRepeat:
+		return strings.Repeat(sampleOf(re.Sub[0]), re.Min)
+	case syntax.OpConcat:
+		var b strings.Builder
+		for _, sub := range re.Sub {
+			b.WriteString(sampleOf(sub))
+		}
+
+		return b.String()
+	case syntax.OpAlternate:
+		return sampleOf(re.Sub[0])
+	default: // empty, star, quest and assertions match the empty string
+		return ""
+	}
+}
+
+// sampleRune returns a readable rune of the ranges of a character class if possible.
+func sampleRune(ranges []rune) rune {
+	for _, r := range "a0_A-" {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if ranges[i] <= r && r <= ranges[i+1] {
+				return r
+			}
+		}
+	}
+
+	if len(ranges) == 0 {
+		return '_'
+	}
+
+	return ranges[0]
+}
+
+func methodsOverlap(a, b []string) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return true // routes without methods match all of them
+	}
+
+	for _, m := range a {
+		for _, n := range b {
+			if m == n {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func formatMethods(methods []string) string {
+	if len(methods) == 0 {
+		return "*"
+	}
+
+	return strings.Join(methods, ",")
+}
@@ -0,0 +1,181 @@
+package smis_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestService_Validate(t *testing.T) { // nolint: funlen
+	handler := func(_ http.ResponseWriter, _ *http.Request) {}
+
+	testCases := []struct {
+		name     string
+		setup    func(service *smis.Service) error
+		expected []string
+	}{
+		{
+			name: "no conflicts",
+			setup: func(service *smis.Service) error {
+				if _, err := service.RegisterEndpoint("/users/{id}", http.MethodGet, handler); err != nil {
+					return err
+				}
+
+				if _, err := service.RegisterEndpoint("/users/{id}", http.MethodDelete, handler); err != nil {
+					return err
+				}
+
+				if _, err := service.RegisterEndpoint("/users/{id}/friends", http.MethodGet, handler); err != nil {
+					return err
+				}
+
+				_, err := service.RegisterEndpointToPublicChain("/users/{id}", http.MethodGet, handler)
+
+				return err
+			},
+		},
+		{
+			name: "duplicate",
+			setup: func(service *smis.Service) error {
+				if _, err := service.RegisterEndpoint("/users/{id}", http.MethodGet, handler); err != nil {
+					return err
+				}
+
+				_, err := service.RegisterEndpoint("/users/{name}", http.MethodGet, handler)
+
+				return err
+			},
+			expected: []string{
+				"route conflict: GET /users/{name} is registered twice (chain default and chain default), " +
+					"the second registration is never matched",
+			},
+		},
+		{
+			name: "variables with different patterns",
+			setup: func(service *smis.Service) error {
+				if _, err := service.RegisterEndpoint("/users/{id:[0-9]+}", http.MethodGet, handler); err != nil {
+					return err
+				}
+
+				_, err := service.RegisterEndpoint("/users/{name:[a-z]+}", http.MethodGet, handler)
+
+				return err
+			},
+		},
+		{
+			name: "shadowed by variable with wider pattern",
+			setup: func(service *smis.Service) error {
+				if _, err := service.RegisterEndpoint("/orders/{id}", http.MethodGet, handler); err != nil {
+					return err
+				}
+
+				_, err := service.RegisterEndpoint("/orders/{id:[0-9]+}", http.MethodGet, handler)
+
+				return err
+			},
+			expected: []string{
+				"route conflict: GET /orders/{id:[0-9]+} of chain default is unreachable, it is shadowed by " +
+					"GET /orders/{id} of chain default",
+			},
+		},
+		{
+			name: "shadowed by variable and file server",
+			setup: func(service *smis.Service) error {
+				if _, err := service.RegisterEndpoint("/users/{id}", http.MethodGet, handler); err != nil {
+					return err
+				}
+
+				if _, err := service.RegisterEndpoint("/users/me", http.MethodGet, handler); err != nil {
+					return err
+				}
+
+				if _, err := service.RegisterFileServer("/static/", http.MethodGet, "."); err != nil {
+					return err
+				}
+
+				_, err := service.RegisterEndpoint("/static/config.json", http.MethodGet, handler)
+
+				return err
+			},
+			expected: []string{
+				"route conflict: GET /users/me of chain default is unreachable, it is shadowed by GET /users/{id} " +
+					"of chain default",
+				"route conflict: GET /static/config.json of chain default is unreachable, it is shadowed by GET " +
+					"/static/ of chain default",
+			},
+		},
+		{
+			name: "default chain collides with chain prefix",
+			setup: func(service *smis.Service) error {
+				if _, err := service.RegisterEndpoint("/public/weather", http.MethodGet, handler); err != nil {
+					return err
+				}
+
+				_, err := service.RegisterEndpointToPublicChain("/weather", http.MethodGet, handler)
+
+				return err
+			},
+			expected: []string{
+				"route conflict: GET /public/weather of chain default collides with the prefix /public of chain public",
+				"route conflict: GET /public/weather is registered twice (chain default and chain public), " +
+					"the second registration is never matched",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			service, err := smis.NewService(&http.Server{}, mux.NewRouter(), logrus.New())
+			if err != nil {
+				t.Fatalf("failed to create service: %s", err)
+			}
+
+			if err = testCase.setup(service); err != nil {
+				t.Fatalf("failed to register routes: %s", err)
+			}
+
+			err = service.Validate()
+			if len(testCase.expected) == 0 {
+				if err != nil {
+					t.Errorf("expected no error but got: %s", err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, smis.ErrRouteConflict) {
+				t.Fatalf("expected error to be a route conflict but got: %v", err)
+			}
+
+			if expected := strings.Join(testCase.expected, "; "); expected != err.Error() {
+				t.Errorf("expected error\n%s\nbut got\n%s", expected, err)
+			}
+		})
+	}
+}
+
+func TestService_ListenAndServe_RouteConflict(t *testing.T) {
+	service, err := smis.NewService(&http.Server{}, mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	handler := func(_ http.ResponseWriter, _ *http.Request) {}
+
+	for i := 0; i < 2; i++ {
+		if _, err = service.RegisterEndpoint("/ping", http.MethodGet, handler); err != nil {
+			t.Fatalf("failed to register endpoint: %s", err)
+		}
+	}
+
+	if err = service.ListenAndServe(); !errors.Is(err, smis.ErrRouteConflict) {
+		t.Errorf("expected route conflict but got: %v", err)
+	}
+}
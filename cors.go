@@ -0,0 +1,102 @@
+package smis
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis/middleware/cors"
+)
+
+// WithCORS sets the CORS policy of the chain, it replaces a policy set before. Requests are handled by the policy of
+// the chain owning the route, the policy of the default chain applies only to chains without own policy. Custom
+// chains are created if they don't exist, see AddMiddleware().
+func (s *Service) WithCORS(chain string, config cors.Config) (*Service, error) {
+	if err := config.Validate(); err != nil {
+		return s, fmt.Errorf("invalid CORS config for chain %s: %w", chain, err)
+	}
+
+	s.setCORSPolicy(chain, config)
+
+	return s, nil
+}
+
+// setCORSPolicy sets the policy of the chain. An invalid config is logged, its invalid origins never match.
+func (s *Service) setCORSPolicy(chain string, config cors.Config) {
+	if s.corsPolicies == nil {
+		s.corsPolicies = make(map[string]mux.MiddlewareFunc)
+	}
+
+	_, exists := s.corsPolicies[chain]
+	s.corsPolicies[chain] = cors.NewWithLog(s.Log, s.Router, config)
+
+	if !exists {
+		s.AddMiddleware(chain, s.corsMiddleware(chain))
+	}
+}
+
+// corsMiddleware applies the policy of the chain to the request, if the chain owns the policy of the request. As
+// the middleware of the default chain is executed for all chains, this prevents two policies handling one request.
+func (s *Service) corsMiddleware(chain string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			routeChain := MiddlewareChainDefault
+			if route := mux.CurrentRoute(request); route != nil {
+				routeChain = s.chainOfRoute(route)
+			}
+
+			policy, owner := s.corsPolicy(routeChain)
+			if policy == nil || owner != chain {
+				next.ServeHTTP(writer, request)
+				return
+			}
+
+			policy(next).ServeHTTP(writer, request)
+		})
+	}
+}
+
+// corsPolicy returns the policy of the chain owning the route and the chain owning the policy. It falls back to the
+// policy of the default chain and returns nil if there is none.
+func (s *Service) corsPolicy(chain string) (mux.MiddlewareFunc, string) {
+	if policy, ok := s.corsPolicies[chain]; ok {
+		return policy, chain
+	}
+
+	return s.corsPolicies[MiddlewareChainDefault], MiddlewareChainDefault
+}
+
+// servePreflight answers preflight requests to routes without OPTIONS method, as mux doesn't execute the middleware
+// of any chain for them. It returns false if the request is no preflight request or no policy applies.
+func (s *Service) servePreflight(writer http.ResponseWriter, request *http.Request) bool {
+	if !cors.IsPreflight(request) {
+		return false
+	}
+
+	policy, _ := s.corsPolicy(s.chainOfPreflight(request))
+	if policy == nil {
+		return false
+	}
+
+	policy(http.NotFoundHandler()).ServeHTTP(writer, request) // the policy answers preflight requests itself
+
+	return true
+}
+
+// chainOfPreflight returns the chain owning the route matching the requested method. If the method isn't allowed,
+// the chain of a route matching the path with another method is returned.
+func (s *Service) chainOfPreflight(request *http.Request) string {
+	methods := append([]string{request.Header.Get(cors.HeaderACRM)}, s.getMethodsForPath(request)...)
+
+	for _, method := range methods {
+		simReq := &http.Request{Method: method, URL: request.URL, RequestURI: request.RequestURI}
+
+		match := &mux.RouteMatch{}
+		if s.Router.Match(simReq, match) && match.MatchErr == nil {
+			return s.chainOfRoute(match.Route)
+		}
+	}
+
+	return MiddlewareChainDefault
+}
@@ -0,0 +1,281 @@
+package smis_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/go-utils/slice"
+	"github.com/rebel-l/smis"
+	"github.com/rebel-l/smis/middleware/cors"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestService_WithCORS(t *testing.T) { // nolint: funlen, gocognit
+	const (
+		console = "https://console.example.com"
+		partner = "https://partner.example.org"
+		other   = "https://other.example.net"
+	)
+
+	service, err := smis.NewService(&http.Server{}, mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	service.
+		WithDefaultMiddleware(cors.Config{AccessControlAllowOrigins: slice.StringSlice{other}}).
+		WithDefaultMiddlewareForPRChain(cors.Config{AccessControlAllowOrigins: slice.StringSlice{"*"}})
+
+	_, err = service.WithCORS(smis.MiddlewareChainRestricted, cors.Config{
+		AccessControlAllowOrigins: slice.StringSlice{console},
+		AllowCredentials:          true,
+		Strict:                    true,
+	})
+	if err != nil {
+		t.Fatalf("failed to set CORS policy: %s", err)
+	}
+
+	service.GetRouterForMiddlewareChain("partner")
+
+	_, err = service.WithCORS("partner", cors.Config{AccessControlAllowOrigins: slice.StringSlice{partner}})
+	if err != nil {
+		t.Fatalf("failed to set CORS policy: %s", err)
+	}
+
+	created := func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusCreated)
+	}
+
+	for _, chain := range []string{smis.MiddlewareChainDefault, smis.MiddlewareChainPublic,
+		smis.MiddlewareChainRestricted, "partner"} {
+		if _, err = service.RegisterEndpointToChain(chain, "/items", http.MethodPost, created); err != nil {
+			t.Fatalf("failed to register endpoint: %s", err)
+		}
+	}
+
+	testCases := []struct {
+		name                string
+		method              string
+		path                string
+		origin              string
+		expectedStatus      int
+		expectedOrigin      string
+		expectedCredentials string
+	}{
+		{
+			name:           "default chain",
+			method:         http.MethodPost,
+			path:           "/items",
+			origin:         other,
+			expectedStatus: http.StatusCreated,
+			expectedOrigin: other,
+		},
+		{
+			name:           "public chain allows any origin",
+			method:         http.MethodPost,
+			path:           "/public/items",
+			origin:         partner,
+			expectedStatus: http.StatusCreated,
+			expectedOrigin: partner,
+		},
+		{
+			name:                "restricted chain allows console",
+			method:              http.MethodPost,
+			path:                "/restricted/items",
+			origin:              console,
+			expectedStatus:      http.StatusCreated,
+			expectedOrigin:      console,
+			expectedCredentials: "true",
+		},
+		{
+			name:           "restricted chain forbids other origins",
+			method:         http.MethodPost,
+			path:           "/restricted/items",
+			origin:         other,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:                "restricted chain preflight",
+			method:              http.MethodOptions,
+			path:                "/restricted/items",
+			origin:              console,
+			expectedStatus:      http.StatusNoContent,
+			expectedOrigin:      console,
+			expectedCredentials: "true",
+		},
+		{
+			name:           "restricted chain preflight rejected",
+			method:         http.MethodOptions,
+			path:           "/restricted/items",
+			origin:         partner,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "custom chain",
+			method:         http.MethodPost,
+			path:           "/partner/items",
+			origin:         partner,
+			expectedStatus: http.StatusCreated,
+			expectedOrigin: partner,
+		},
+		{
+			name:           "custom chain preflight",
+			method:         http.MethodOptions,
+			path:           "/partner/items",
+			origin:         partner,
+			expectedStatus: http.StatusNoContent,
+			expectedOrigin: partner,
+		},
+		{
+			name:           "custom chain ignores policy of default chain",
+			method:         http.MethodPost,
+			path:           "/partner/items",
+			origin:         other,
+			expectedStatus: http.StatusCreated,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := httptest.NewRequest(testCase.method, testCase.path, nil)
+			request.Header.Set(cors.HeaderOrigin, testCase.origin)
+
+			if testCase.method == http.MethodOptions {
+				request.Header.Set(cors.HeaderACRM, http.MethodPost)
+			}
+
+			w := httptest.NewRecorder()
+			service.Router.ServeHTTP(w, request)
+			resp := w.Result()
+			if err := resp.Body.Close(); err != nil {
+				t.Fatalf("failed to close body: %s", err)
+			}
+
+			if testCase.expectedStatus != resp.StatusCode {
+				t.Errorf("expected status %d but got %d", testCase.expectedStatus, resp.StatusCode)
+			}
+
+			origin := resp.Header.Get(cors.HeaderACAO)
+			if testCase.expectedOrigin != origin {
+				t.Errorf("expected origin '%s' but got '%s'", testCase.expectedOrigin, origin)
+			}
+
+			credentials := resp.Header.Get(cors.HeaderACAC)
+			if testCase.expectedCredentials != credentials {
+				t.Errorf("expected credentials '%s' but got '%s'", testCase.expectedCredentials, credentials)
+			}
+
+			if vary := resp.Header.Values(cors.HeaderVary); len(vary) != 1 {
+				t.Errorf("expected one policy to handle the request but got vary headers %v", vary)
+			}
+		})
+	}
+}
+
+func TestService_WithCORS_Invalid(t *testing.T) {
+	service, err := smis.NewService(&http.Server{}, mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	_, err = service.WithCORS(smis.MiddlewareChainPublic, cors.Config{
+		AccessControlAllowOrigins: slice.StringSlice{"https://*.*.example.com"},
+	})
+	if !errors.Is(err, cors.ErrInvalidOrigin) {
+		t.Errorf("expected error '%v' but got '%v'", cors.ErrInvalidOrigin, err)
+	}
+}
+
+func TestService_WithCORS_OwningChain(t *testing.T) { // nolint: funlen
+	const (
+		internal = "https://internal.example.com"
+		partner  = "https://partner.example.org"
+	)
+
+	service, err := smis.NewService(&http.Server{}, mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	handler := func(_ http.ResponseWriter, _ *http.Request) {}
+
+	// registered at the default chain before the public chain exists, so the default chain owns it despite its prefix
+	if _, err = service.RegisterEndpoint("/public/status", http.MethodGet, handler); err != nil {
+		t.Fatalf("failed to register endpoint: %s", err)
+	}
+
+	if _, err = service.RegisterEndpointToPublicChain("/items", http.MethodGet, handler); err != nil {
+		t.Fatalf("failed to register endpoint: %s", err)
+	}
+
+	if _, err = service.WithCORS(smis.MiddlewareChainDefault, cors.Config{
+		AccessControlAllowOrigins: slice.StringSlice{internal},
+	}); err != nil {
+		t.Fatalf("failed to set CORS policy: %s", err)
+	}
+
+	if _, err = service.WithCORS(smis.MiddlewareChainPublic, cors.Config{
+		AccessControlAllowOrigins: slice.StringSlice{partner},
+	}); err != nil {
+		t.Fatalf("failed to set CORS policy: %s", err)
+	}
+
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		origin         string
+		expectedOrigin string
+	}{
+		{
+			name:           "route of default chain with prefix of public chain",
+			method:         http.MethodGet,
+			path:           "/public/status",
+			origin:         internal,
+			expectedOrigin: internal,
+		},
+		{
+			name:   "route of default chain ignores policy of public chain",
+			method: http.MethodGet,
+			path:   "/public/status",
+			origin: partner,
+		},
+		{
+			name:           "preflight of route of default chain with prefix of public chain",
+			method:         http.MethodOptions,
+			path:           "/public/status",
+			origin:         internal,
+			expectedOrigin: internal,
+		},
+		{
+			name:           "route of public chain",
+			method:         http.MethodGet,
+			path:           "/public/items",
+			origin:         partner,
+			expectedOrigin: partner,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := httptest.NewRequest(testCase.method, testCase.path, nil)
+			request.Header.Set(cors.HeaderOrigin, testCase.origin)
+
+			if testCase.method == http.MethodOptions {
+				request.Header.Set(cors.HeaderACRM, http.MethodGet)
+			}
+
+			w := httptest.NewRecorder()
+			service.Router.ServeHTTP(w, request)
+
+			if origin := w.Header().Get(cors.HeaderACAO); testCase.expectedOrigin != origin {
+				t.Errorf("expected origin '%s' but got '%s'", testCase.expectedOrigin, origin)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package smis
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/vmihailenco/msgpack/v4"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// HeaderKeyAccept represents the key in the header for the accepted media types
+	HeaderKeyAccept = "Accept"
+
+	// HeaderKeyVary represents the key in the header for the request headers a response varies on
+	HeaderKeyVary = "Vary"
+
+	// HeaderContentTypeXML represent the value for content type XML in the header
+	HeaderContentTypeXML = "application/xml"
+
+	// HeaderContentTypeYAML represent the value for content type YAML in the header
+	HeaderContentTypeYAML = "application/yaml"
+
+	// HeaderContentTypeMessagePack represent the value for content type MessagePack in the header
+	HeaderContentTypeMessagePack = "application/msgpack"
+)
+
+// Encoder encodes payloads to one format. MediaTypes returns the media types the encoder produces, the first one is
+// the preferred.
+type Encoder interface {
+	MediaTypes() []string
+	Encode(payload interface{}) ([]byte, error)
+}
+
+// JSONEncoder encodes payloads to JSON.
+type JSONEncoder struct{}
+
+// MediaTypes returns the media types of JSON.
+func (JSONEncoder) MediaTypes() []string {
+	return []string{HeaderContentTypeJSON}
+}
+
+// Encode encodes the payload to JSON.
+func (JSONEncoder) Encode(payload interface{}) ([]byte, error) {
+	return json.Marshal(payload)
+}
+
+// XMLEncoder encodes payloads to XML. The payload needs to be supported by encoding/xml.
+type XMLEncoder struct{}
+
+// MediaTypes returns the media types of XML.
+func (XMLEncoder) MediaTypes() []string {
+	return []string{HeaderContentTypeXML, "text/xml"}
+}
+
+// Encode encodes the payload to XML.
+func (XMLEncoder) Encode(payload interface{}) ([]byte, error) {
+	body, err := xml.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
+
+// YAMLEncoder encodes payloads to YAML. The payload is encoded to JSON first, so the JSON struct tags apply.
+type YAMLEncoder struct{}
+
+// MediaTypes returns the media types of YAML.
+func (YAMLEncoder) MediaTypes() []string {
+	return []string{HeaderContentTypeYAML, "application/x-yaml", "text/yaml"}
+}
+
+// Encode encodes the payload to YAML.
+func (YAMLEncoder) Encode(payload interface{}) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var document interface{}
+	if err = yaml.Unmarshal(body, &document); err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(document)
+}
+
+// MessagePackEncoder encodes payloads to MessagePack. The JSON struct tags apply.
+type MessagePackEncoder struct{}
+
+// MediaTypes returns the media types of MessagePack.
+func (MessagePackEncoder) MediaTypes() []string {
+	return []string{HeaderContentTypeMessagePack, "application/x-msgpack"}
+}
+
+// Encode encodes the payload to MessagePack.
+func (MessagePackEncoder) Encode(payload interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	encoder := msgpack.NewEncoder(&buffer).UseJSONTag(true)
+	if err := encoder.Encode(payload); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// Encoders is a registry of encoders used for content negotiation. The order of registration is the preference of
+// the server if the client accepts several media types equally.
+type Encoders struct {
+	mutex    sync.RWMutex
+	encoders []Encoder
+}
+
+// NewEncoders returns a registry containing the given encoders.
+func NewEncoders(encoders ...Encoder) *Encoders {
+	e := &Encoders{}
+	for _, encoder := range encoders {
+		e.Register(encoder)
+	}
+
+	return e
+}
+
+// DefaultEncoders returns a registry containing JSON, XML, YAML and MessagePack in this order.
+func DefaultEncoders() *Encoders {
+	return NewEncoders(JSONEncoder{}, XMLEncoder{}, YAMLEncoder{}, MessagePackEncoder{})
+}
+
+// Register adds an encoder to the registry. An encoder registered before with the same preferred media type is
+// replaced.
+func (e *Encoders) Register(encoder Encoder) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for i, existing := range e.encoders {
+		if existing.MediaTypes()[0] == encoder.MediaTypes()[0] {
+			e.encoders[i] = encoder
+			return
+		}
+	}
+
+	e.encoders = append(e.encoders, encoder)
+}
+
+// MediaTypes returns the preferred media types of all encoders.
+func (e *Encoders) MediaTypes() []string {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	mediaTypes := make([]string, len(e.encoders))
+	for i, encoder := range e.encoders {
+		mediaTypes[i] = encoder.MediaTypes()[0]
+	}
+
+	return mediaTypes
+}
+
+// Negotiate returns the encoder and media type best matching the Accept header. Media ranges are weighted by their
+// q-value, more specific ranges take precedence over less specific ones. An empty header accepts everything. If no
+// encoder matches, false is returned.
+func (e *Encoders) Negotiate(accept string) (Encoder, string, bool) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	if strings.TrimSpace(accept) == "" {
+		accept = "*/*"
+	}
+
+	ranges := parseAccept(accept)
+
+	var (
+		best            Encoder
+		bestType        string
+		bestQuality     float64
+		bestSpecificity int
+	)
+
+	for _, encoder := range e.encoders {
+		for _, mediaType := range encoder.MediaTypes() {
+			// on equal quality the more specific range wins, then the order of registration
+			quality, specificity := ranges.quality(mediaType)
+			if quality > bestQuality || (quality > 0 && quality == bestQuality && specificity > bestSpecificity) {
+				best, bestType, bestQuality, bestSpecificity = encoder, mediaType, quality, specificity
+			}
+		}
+	}
+
+	return best, bestType, best != nil
+}
+
+type mediaRange struct {
+	mediaType   string
+	quality     float64
+	specificity int
+}
+
+type mediaRanges []mediaRange
+
+func parseAccept(accept string) mediaRanges {
+	var ranges mediaRanges
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+
+		specificity := 2
+
+		switch {
+		case mediaType == "*/*":
+			specificity = 0
+		case strings.HasSuffix(mediaType, "/*"):
+			specificity = 1
+		}
+
+		ranges = append(ranges, mediaRange{mediaType: mediaType, quality: quality, specificity: specificity})
+	}
+
+	// the most specific range matching a media type defines its quality
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].specificity > ranges[j].specificity
+	})
+
+	return ranges
+}
+
+// quality returns the quality of the media type and the specificity of the range defining it.
+func (m mediaRanges) quality(mediaType string) (float64, int) {
+	for _, r := range m {
+		if r.matches(mediaType) {
+			return r.quality, r.specificity
+		}
+	}
+
+	return 0, 0
+}
+
+func (r mediaRange) matches(mediaType string) bool {
+	switch r.specificity {
+	case 0:
+		return true
+	case 1:
+		return strings.HasPrefix(mediaType, strings.TrimSuffix(r.mediaType, "*"))
+	default:
+		return r.mediaType == mediaType
+	}
+}
@@ -0,0 +1,124 @@
+package smis_test
+
+import (
+	"testing"
+
+	"github.com/rebel-l/smis"
+)
+
+func TestEncoders_Negotiate(t *testing.T) { // nolint: funlen
+	testCases := []struct {
+		name              string
+		accept            string
+		expectedMediaType string
+		expectedOK        bool
+	}{
+		{
+			name:              "empty",
+			expectedMediaType: smis.HeaderContentTypeJSON,
+			expectedOK:        true,
+		},
+		{
+			name:              "any",
+			accept:            "*/*",
+			expectedMediaType: smis.HeaderContentTypeJSON,
+			expectedOK:        true,
+		},
+		{
+			name:              "exact",
+			accept:            "application/xml",
+			expectedMediaType: smis.HeaderContentTypeXML,
+			expectedOK:        true,
+		},
+		{
+			name:              "alias",
+			accept:            "text/xml",
+			expectedMediaType: "text/xml",
+			expectedOK:        true,
+		},
+		{
+			name:              "q-values",
+			accept:            "application/json;q=0.5, application/msgpack;q=0.9, */*;q=0.1",
+			expectedMediaType: smis.HeaderContentTypeMessagePack,
+			expectedOK:        true,
+		},
+		{
+			name:              "specific range overrules wildcard",
+			accept:            "application/*, application/json;q=0",
+			expectedMediaType: smis.HeaderContentTypeXML,
+			expectedOK:        true,
+		},
+		{
+			name:              "server preference on equal quality",
+			accept:            "application/yaml, application/xml",
+			expectedMediaType: smis.HeaderContentTypeXML,
+			expectedOK:        true,
+		},
+		{
+			name:              "specific range before wildcard on equal quality",
+			accept:            "application/xml, */*",
+			expectedMediaType: smis.HeaderContentTypeXML,
+			expectedOK:        true,
+		},
+		{
+			name:              "specific alias before wildcard on equal quality",
+			accept:            "text/xml, */*",
+			expectedMediaType: "text/xml",
+			expectedOK:        true,
+		},
+		{
+			name:              "invalid q-value is skipped",
+			accept:            "application/xml;q=high, application/yaml",
+			expectedMediaType: smis.HeaderContentTypeYAML,
+			expectedOK:        true,
+		},
+		{
+			name:   "not acceptable",
+			accept: "text/html, image/*",
+		},
+		{
+			name:   "excluded by q=0",
+			accept: "*/*;q=0",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, mediaType, ok := smis.DefaultEncoders().Negotiate(testCase.accept)
+
+			if testCase.expectedOK != ok {
+				t.Errorf("expected ok to be %t but got %t", testCase.expectedOK, ok)
+			}
+
+			if testCase.expectedMediaType != mediaType {
+				t.Errorf("expected media type '%s' but got '%s'", testCase.expectedMediaType, mediaType)
+			}
+		})
+	}
+}
+
+type csvEncoder struct{}
+
+func (csvEncoder) MediaTypes() []string {
+	return []string{smis.HeaderContentTypeJSON}
+}
+
+func (csvEncoder) Encode(_ interface{}) ([]byte, error) {
+	return []byte("replaced"), nil
+}
+
+func TestEncoders_Register(t *testing.T) {
+	encoders := smis.NewEncoders(smis.JSONEncoder{}, smis.XMLEncoder{})
+	encoders.Register(csvEncoder{})
+
+	if got := encoders.MediaTypes(); len(got) != 2 {
+		t.Fatalf("expected encoder to be replaced but got media types %v", got)
+	}
+
+	encoder, _, _ := encoders.Negotiate(smis.HeaderContentTypeJSON)
+
+	body, err := encoder.Encode(nil)
+	if err != nil || string(body) != "replaced" {
+		t.Errorf("expected replaced encoder to be used but got '%s' with error %v", body, err)
+	}
+}
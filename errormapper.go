@@ -0,0 +1,75 @@
+package smis
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+)
+
+// ErrorMapper maps errors returned by handlers to problems. Mappings are checked in the order they were added, the
+// first matching one defines the status. A *RequestError is always mapped to its own status and field errors.
+// Unmapped errors result in an internal server error, their message is not exposed to the client.
+type ErrorMapper struct {
+	mappings []errorMapping
+}
+
+type errorMapping struct {
+	match  func(err error) bool
+	status int
+}
+
+// NewErrorMapper returns an ErrorMapper without mappings.
+func NewErrorMapper() *ErrorMapper {
+	return &ErrorMapper{}
+}
+
+// Register maps errors matching target with errors.Is() to the status.
+func (m *ErrorMapper) Register(target error, status int) *ErrorMapper {
+	m.mappings = append(m.mappings, errorMapping{
+		match: func(err error) bool {
+			return errors.Is(err, target)
+		},
+		status: status,
+	})
+
+	return m
+}
+
+// RegisterType maps errors of the same type as target with errors.As() to the status, e.g.
+// RegisterType(&NotFoundError{}, http.StatusNotFound).
+func (m *ErrorMapper) RegisterType(target error, status int) *ErrorMapper {
+	t := reflect.TypeOf(target)
+
+	m.mappings = append(m.mappings, errorMapping{
+		match: func(err error) bool {
+			return errors.As(err, reflect.New(t).Interface())
+		},
+		status: status,
+	})
+
+	return m
+}
+
+// Map returns the problem for the error. The error message is used as detail, except for server errors (5xx).
+func (m *ErrorMapper) Map(err error) Problem {
+	var requestErr *RequestError
+	if errors.As(err, &requestErr) {
+		return requestErr.Problem()
+	}
+
+	if m != nil {
+		for _, mapping := range m.mappings {
+			if !mapping.match(err) {
+				continue
+			}
+
+			if mapping.status >= http.StatusInternalServerError {
+				return NewProblem(mapping.status, "")
+			}
+
+			return NewProblem(mapping.status, err.Error())
+		}
+	}
+
+	return NewProblem(http.StatusInternalServerError, "")
+}
@@ -0,0 +1,80 @@
+package smis_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/rebel-l/smis"
+)
+
+var errNotFoundTest = errors.New("user not found")
+
+type conflictErrorTest struct {
+	id string
+}
+
+func (e *conflictErrorTest) Error() string {
+	return fmt.Sprintf("user %s already exists", e.id)
+}
+
+func TestErrorMapper_Map(t *testing.T) { // nolint: funlen
+	mapper := smis.NewErrorMapper().
+		Register(errNotFoundTest, http.StatusNotFound).
+		RegisterType(&conflictErrorTest{}, http.StatusConflict).
+		Register(http.ErrHandlerTimeout, http.StatusServiceUnavailable)
+
+	testCases := []struct {
+		name     string
+		mapper   *smis.ErrorMapper
+		err      error
+		expected smis.Problem
+	}{
+		{
+			name:     "sentinel",
+			mapper:   mapper,
+			err:      fmt.Errorf("load: %w", errNotFoundTest),
+			expected: smis.NewProblem(http.StatusNotFound, "load: user not found"),
+		},
+		{
+			name:     "type",
+			mapper:   mapper,
+			err:      fmt.Errorf("create: %w", &conflictErrorTest{id: "42"}),
+			expected: smis.NewProblem(http.StatusConflict, "create: user 42 already exists"),
+		},
+		{
+			name:     "server error hides detail",
+			mapper:   mapper,
+			err:      http.ErrHandlerTimeout,
+			expected: smis.NewProblem(http.StatusServiceUnavailable, ""),
+		},
+		{
+			name:     "request error",
+			mapper:   mapper,
+			err:      &smis.RequestError{Status: http.StatusBadRequest, Detail: "invalid parameters"},
+			expected: smis.NewProblem(http.StatusBadRequest, "invalid parameters"),
+		},
+		{
+			name:     "unmapped",
+			mapper:   mapper,
+			err:      errors.New("database down"),
+			expected: smis.NewProblem(http.StatusInternalServerError, ""),
+		},
+		{
+			name:     "nil mapper",
+			err:      errNotFoundTest,
+			expected: smis.NewProblem(http.StatusInternalServerError, ""),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := testCase.mapper.Map(testCase.err)
+			if !reflect.DeepEqual(testCase.expected, got) {
+				t.Errorf("expected %+v but got %+v", testCase.expected, got)
+			}
+		})
+	}
+}
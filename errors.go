@@ -0,0 +1,59 @@
+package smis
+
+import (
+	"errors"
+	"strings"
+)
+
+// Errors represents a collection of errors which occurred independently of each other.
+type Errors []error
+
+// Append adds the error to the collection if it is not nil.
+func (e Errors) Append(err error) Errors {
+	if err == nil {
+		return e
+	}
+
+	return append(e, err)
+}
+
+// ErrorOrNil returns nil if the collection is empty, otherwise the collection itself.
+func (e Errors) ErrorOrNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// Error returns the messages of all errors separated by semicolon.
+func (e Errors) Error() string {
+	msg := make([]string, len(e))
+	for i, err := range e {
+		msg[i] = err.Error()
+	}
+
+	return strings.Join(msg, "; ")
+}
+
+// Is reports whether any error of the collection matches the target.
+func (e Errors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first error of the collection that matches the target.
+func (e Errors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
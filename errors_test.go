@@ -0,0 +1,65 @@
+package smis_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rebel-l/smis"
+)
+
+func TestErrors(t *testing.T) {
+	testCases := []struct {
+		name            string
+		errs            smis.Errors
+		expectedNil     bool
+		expectedMessage string
+		expectedIs      bool
+	}{
+		{
+			name:        "empty",
+			expectedNil: true,
+		},
+		{
+			name:        "nil errors are skipped",
+			errs:        smis.Errors{}.Append(nil).Append(nil),
+			expectedNil: true,
+		},
+		{
+			name:            "single",
+			errs:            smis.Errors{}.Append(errors.New("first")),
+			expectedMessage: "first",
+		},
+		{
+			name:            "multiple",
+			errs:            smis.Errors{}.Append(errors.New("first")).Append(http.ErrServerClosed),
+			expectedMessage: "first; http: Server closed",
+			expectedIs:      true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.errs.ErrorOrNil()
+			if testCase.expectedNil {
+				if err != nil {
+					t.Errorf("expected nil but got '%s'", err)
+				}
+
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expected an error but got nil")
+			}
+
+			if testCase.expectedMessage != err.Error() {
+				t.Errorf("expected message '%s' but got '%s'", testCase.expectedMessage, err)
+			}
+
+			if testCase.expectedIs != errors.Is(err, http.ErrServerClosed) {
+				t.Errorf("expected errors.Is to be %t", testCase.expectedIs)
+			}
+		})
+	}
+}
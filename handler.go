@@ -0,0 +1,57 @@
+package smis
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis/middleware/writer"
+)
+
+// HandlerFuncE is a handler returning an error. The error is mapped to a problem response by the ErrorMapper of the
+// service, see Service.HandlerFunc().
+type HandlerFuncE func(writer http.ResponseWriter, request *http.Request) error
+
+// HandlerFunc converts a HandlerFuncE to a http.HandlerFunc. A returned error is logged with the request ID and
+// mapped to a problem response by the ErrorMapper. If the handler already wrote the response, the error is only logged.
+func (s *Service) HandlerFunc(f HandlerFuncE) http.HandlerFunc {
+	return func(w http.ResponseWriter, request *http.Request) {
+		recorder := writer.NewRecorder(w)
+
+		err := f(recorder, request)
+		if err == nil {
+			return
+		}
+
+		problem := s.ErrorMapper.Map(err)
+		log := s.NewLogForRequestID(request.Context())
+
+		if problem.Status >= http.StatusInternalServerError {
+			log.Errorf("handler failed: %v", err)
+		} else {
+			log.Infof("handler failed: %v", err)
+		}
+
+		if recorder.WroteHeader() {
+			log.Warn("response already written, error is not sent")
+			return
+		}
+
+		response := &Response{Log: log}
+		response.WriteProblem(recorder, request, problem)
+	}
+}
+
+// RegisterEndpointE registers a handler returning an error at the default chain.
+func (s *Service) RegisterEndpointE(
+	path, method string, f HandlerFuncE, opts ...EndpointOption,
+) (*mux.Route, error) {
+	return s.RegisterEndpointToChainE(MiddlewareChainDefault, path, method, f, opts...)
+}
+
+// RegisterEndpointToChainE registers a handler returning an error at the given chain.
+func (s *Service) RegisterEndpointToChainE(
+	chain, path, method string, f HandlerFuncE, opts ...EndpointOption,
+) (*mux.Route, error) {
+	return s.RegisterEndpointToChain(chain, path, method, s.HandlerFunc(f), opts...)
+}
@@ -0,0 +1,117 @@
+package smis_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestService_RegisterEndpointE(t *testing.T) { // nolint: funlen
+	testCases := []struct {
+		name           string
+		handler        smis.HandlerFuncE
+		expectedStatus int
+		expectedBody   string
+		expectedLevel  logrus.Level
+		expectedLogs   int
+	}{
+		{
+			name: "success",
+			handler: func(writer http.ResponseWriter, _ *http.Request) error {
+				writer.WriteHeader(http.StatusNoContent)
+				return nil
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "mapped error",
+			handler: func(_ http.ResponseWriter, _ *http.Request) error {
+				return errNotFoundTest
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{"detail":"user not found","instance":"/chain/user","status":404,"title":"Not Found",` +
+				`"type":"about:blank"}`,
+			expectedLevel: logrus.InfoLevel,
+			expectedLogs:  1,
+		},
+		{
+			name: "unmapped error",
+			handler: func(_ http.ResponseWriter, _ *http.Request) error {
+				return errors.New("database down")
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{"instance":"/chain/user","status":500,"title":"Internal Server Error",` +
+				`"type":"about:blank"}`,
+			expectedLevel: logrus.ErrorLevel,
+			expectedLogs:  1,
+		},
+		{
+			name: "response already written",
+			handler: func(writer http.ResponseWriter, _ *http.Request) error {
+				writer.WriteHeader(http.StatusAccepted)
+				return errors.New("failed after writing")
+			},
+			expectedStatus: http.StatusAccepted,
+			expectedLevel:  logrus.WarnLevel,
+			expectedLogs:   2,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			log, hook := test.NewNullLogger()
+
+			service, err := smis.NewService(&http.Server{}, mux.NewRouter(), log)
+			if err != nil {
+				t.Fatalf("failed to create service: %s", err)
+			}
+
+			service.ErrorMapper = smis.NewErrorMapper().Register(errNotFoundTest, http.StatusNotFound)
+
+			if _, err = service.RegisterEndpointToChainE("chain", "/user", http.MethodGet, testCase.handler); err != nil {
+				t.Fatalf("failed to register endpoint: %s", err)
+			}
+
+			w := httptest.NewRecorder()
+			service.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/chain/user", nil))
+
+			if testCase.expectedStatus != w.Code {
+				t.Errorf("expected status %d but got %d", testCase.expectedStatus, w.Code)
+			}
+
+			if testCase.expectedBody != w.Body.String() {
+				t.Errorf("expected body '%s' but got '%s'", testCase.expectedBody, w.Body.String())
+			}
+
+			if len(hook.AllEntries()) != testCase.expectedLogs {
+				t.Fatalf("expected %d log entries but got %d", testCase.expectedLogs, len(hook.AllEntries()))
+			}
+
+			if testCase.expectedLogs > 0 && hook.LastEntry().Level != testCase.expectedLevel {
+				t.Errorf("expected log level %s but got %s", testCase.expectedLevel, hook.LastEntry().Level)
+			}
+		})
+	}
+}
+
+func TestService_RegisterEndpointE_NotAllowedMethod(t *testing.T) {
+	service, err := smis.NewService(&http.Server{}, mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	_, err = service.RegisterEndpointE("/user", "CONNECTING", func(_ http.ResponseWriter, _ *http.Request) error {
+		return nil
+	})
+	if err == nil {
+		t.Error("expected an error for not allowed method but got nil")
+	}
+}
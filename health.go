@@ -0,0 +1,71 @@
+package smis
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/rebel-l/smis/health"
+)
+
+const (
+	// HealthPathLiveness is the path of the liveness endpoint
+	HealthPathLiveness = "/healthz"
+
+	// HealthPathReadiness is the path of the readiness endpoint
+	HealthPathReadiness = "/readyz"
+)
+
+// WithHealthEndpoints initializes the health registry and registers the liveness and readiness endpoints at the
+// default chain. Calling it multiple times has no effect.
+func (s *Service) WithHealthEndpoints() (*Service, error) {
+	if s.Health != nil {
+		return s, nil
+	}
+
+	registry := health.NewRegistry()
+
+	if _, err := s.RegisterEndpoint(HealthPathLiveness, http.MethodGet, registry.LivenessHandler()); err != nil {
+		return s, err
+	}
+
+	if _, err := s.RegisterEndpoint(HealthPathReadiness, http.MethodGet, registry.ReadinessHandler()); err != nil {
+		return s, err
+	}
+
+	s.Health = registry
+
+	return s, nil
+}
+
+// RegisterHealthCheck adds a check to the health registry. If the health endpoints are not initialized yet, it does
+// it.
+func (s *Service) RegisterHealthCheck(check health.Check) error {
+	if _, err := s.WithHealthEndpoints(); err != nil {
+		return err
+	}
+
+	return s.Health.Register(check)
+}
+
+// startDraining flips readiness of the service and its listeners to failing and gives load balancers DrainDelay to
+// notice it.
+func (s *Service) startDraining() {
+	draining := false
+
+	for _, service := range append([]*Service{s}, s.listeners...) {
+		if service.Health != nil {
+			service.Health.SetDraining(true)
+
+			draining = true
+		}
+	}
+
+	if !draining {
+		return
+	}
+
+	if s.DrainDelay > 0 {
+		s.Log.Infof("draining for %s before shutdown", s.DrainDelay)
+		time.Sleep(s.DrainDelay)
+	}
+}
@@ -0,0 +1,258 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// StatusUp means the check passed or all critical checks passed
+	StatusUp Status = "up"
+
+	// StatusDegraded means at least one non critical check failed
+	StatusDegraded Status = "degraded"
+
+	// StatusDown means at least one critical check failed or the service is draining
+	StatusDown Status = "down"
+
+	// TimeoutDefault is the timeout used for checks without timeout
+	TimeoutDefault = 5 * time.Second
+)
+
+// Status represents the result of a check or of all checks.
+type Status string
+
+// CheckFunc represents the function executed by a check. A returned error marks the check as down.
+type CheckFunc func(ctx context.Context) error
+
+// Check represents a named health check. Only failing critical checks turn liveness and readiness down, failing non
+// critical checks are reported as degraded. If Interval is greater than zero, the result is cached for this duration.
+type Check struct {
+	Name     string
+	Func     CheckFunc
+	Timeout  time.Duration
+	Critical bool
+	Interval time.Duration
+
+	// Liveness marks a check of the process itself, e.g. a deadlock detection. Liveness executes only these checks,
+	// so a failing dependency makes the service unready but doesn't get it restarted.
+	Liveness bool
+}
+
+// Result represents the outcome of a single check.
+type Result struct {
+	Name      string    `json:"name"`
+	Status    Status    `json:"status"`
+	Critical  bool      `json:"critical"`
+	Latency   string    `json:"latency"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report represents the outcome of all checks.
+type Report struct {
+	Status   Status   `json:"status"`
+	Draining bool     `json:"draining,omitempty"`
+	Checks   []Result `json:"checks"`
+}
+
+type entry struct {
+	check  Check
+	mutex  sync.Mutex
+	result *Result
+}
+
+// Registry holds the registered checks.
+type Registry struct {
+	mutex    sync.RWMutex
+	entries  []*entry
+	draining int32
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a check to the registry. The name of a check must be unique.
+func (r *Registry) Register(check Check) error {
+	if check.Name == "" {
+		return fmt.Errorf("name of health check should not be empty")
+	}
+
+	if check.Func == nil {
+		return fmt.Errorf("function of health check %s should not be nil", check.Name)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, e := range r.entries {
+		if e.check.Name == check.Name {
+			return fmt.Errorf("health check %s is already registered", check.Name)
+		}
+	}
+
+	r.entries = append(r.entries, &entry{check: check})
+
+	return nil
+}
+
+// SetDraining marks the service as draining. A draining service is not ready to receive traffic anymore.
+func (r *Registry) SetDraining(draining bool) {
+	var value int32
+	if draining {
+		value = 1
+	}
+
+	atomic.StoreInt32(&r.draining, value)
+}
+
+// IsDraining returns true if the service is draining.
+func (r *Registry) IsDraining() bool {
+	return atomic.LoadInt32(&r.draining) == 1
+}
+
+// Liveness executes the liveness checks and reports if the service is alive. Without liveness checks the service is
+// alive as long as it responds.
+func (r *Registry) Liveness(ctx context.Context) Report {
+	return r.run(ctx, true)
+}
+
+// Readiness executes all checks and reports if the service is ready to receive traffic.
+func (r *Registry) Readiness(ctx context.Context) Report {
+	report := r.run(ctx, false)
+	if r.IsDraining() {
+		report.Status = StatusDown
+		report.Draining = true
+	}
+
+	return report
+}
+
+// LivenessHandler returns a handler responding the liveness report as JSON.
+func (r *Registry) LivenessHandler() http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		writeReport(writer, r.Liveness(request.Context()))
+	}
+}
+
+// ReadinessHandler returns a handler responding the readiness report as JSON.
+func (r *Registry) ReadinessHandler() http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		writeReport(writer, r.Readiness(request.Context()))
+	}
+}
+
+// run executes all checks or only the liveness checks.
+func (r *Registry) run(ctx context.Context, livenessOnly bool) Report {
+	r.mutex.RLock()
+	entries := make([]*entry, 0, len(r.entries))
+
+	for _, e := range r.entries {
+		if !livenessOnly || e.check.Liveness {
+			entries = append(entries, e)
+		}
+	}
+	r.mutex.RUnlock()
+
+	report := Report{Status: StatusUp, Checks: make([]Result, len(entries))}
+
+	var wg sync.WaitGroup
+
+	for i, e := range entries {
+		wg.Add(1)
+
+		go func(i int, e *entry) {
+			defer wg.Done()
+
+			report.Checks[i] = e.execute(ctx)
+		}(i, e)
+	}
+
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		switch {
+		case result.Status == StatusUp:
+			continue
+		case result.Critical:
+			report.Status = StatusDown
+		case report.Status == StatusUp:
+			report.Status = StatusDegraded
+		}
+	}
+
+	return report
+}
+
+func (e *entry) execute(ctx context.Context) Result {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.result != nil && e.check.Interval > 0 && time.Since(e.result.CheckedAt) < e.check.Interval {
+		return *e.result
+	}
+
+	timeout := e.check.Timeout
+	if timeout <= 0 {
+		timeout = TimeoutDefault
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+
+	go func() {
+		done <- e.check.Func(ctx)
+	}()
+
+	var err error
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := Result{
+		Name:      e.check.Name,
+		Status:    StatusUp,
+		Critical:  e.check.Critical,
+		Latency:   time.Since(start).String(),
+		CheckedAt: start,
+	}
+
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	e.result = &result
+
+	return result
+}
+
+func writeReport(writer http.ResponseWriter, report Report) {
+	code := http.StatusOK
+	if report.Status == StatusDown {
+		code = http.StatusServiceUnavailable
+	}
+
+	body, err := json.Marshal(report)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(code)
+	_, _ = writer.Write(body)
+}
@@ -0,0 +1,223 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rebel-l/smis/health"
+)
+
+func TestRegistry_Register(t *testing.T) {
+	testCases := []struct {
+		name          string
+		check         health.Check
+		expectedError string
+	}{
+		{
+			name:          "no name",
+			check:         health.Check{Func: func(_ context.Context) error { return nil }},
+			expectedError: "name of health check should not be empty",
+		},
+		{
+			name:          "no func",
+			check:         health.Check{Name: "db"},
+			expectedError: "function of health check db should not be nil",
+		},
+		{
+			name:          "duplicate",
+			check:         health.Check{Name: "existing", Func: func(_ context.Context) error { return nil }},
+			expectedError: "health check existing is already registered",
+		},
+		{
+			name:  "success",
+			check: health.Check{Name: "db", Func: func(_ context.Context) error { return nil }},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			registry := health.NewRegistry()
+			if err := registry.Register(health.Check{
+				Name: "existing",
+				Func: func(_ context.Context) error { return nil },
+			}); err != nil {
+				t.Fatalf("failed to register check: %s", err)
+			}
+
+			err := registry.Register(testCase.check)
+			if testCase.expectedError == "" && err != nil {
+				t.Errorf("expected no error but got: %s", err)
+			}
+
+			if testCase.expectedError != "" && (err == nil || testCase.expectedError != err.Error()) {
+				t.Errorf("expected error '%s' but got '%v'", testCase.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestRegistry_Readiness(t *testing.T) { // nolint: funlen
+	ok := func(_ context.Context) error { return nil }
+	fail := func(_ context.Context) error { return fmt.Errorf("connection refused") }
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}
+
+	testCases := []struct {
+		name           string
+		checks         []health.Check
+		draining       bool
+		expectedStatus health.Status
+		expectedCode   int
+	}{
+		{
+			name:           "no checks",
+			expectedStatus: health.StatusUp,
+			expectedCode:   http.StatusOK,
+		},
+		{
+			name: "all up",
+			checks: []health.Check{
+				{Name: "db", Func: ok, Critical: true},
+				{Name: "cache", Func: ok},
+			},
+			expectedStatus: health.StatusUp,
+			expectedCode:   http.StatusOK,
+		},
+		{
+			name: "non critical down",
+			checks: []health.Check{
+				{Name: "db", Func: ok, Critical: true},
+				{Name: "cache", Func: fail},
+			},
+			expectedStatus: health.StatusDegraded,
+			expectedCode:   http.StatusOK,
+		},
+		{
+			name: "critical down",
+			checks: []health.Check{
+				{Name: "db", Func: fail, Critical: true},
+				{Name: "cache", Func: fail},
+			},
+			expectedStatus: health.StatusDown,
+			expectedCode:   http.StatusServiceUnavailable,
+		},
+		{
+			name: "critical timeout",
+			checks: []health.Check{
+				{Name: "db", Func: slow, Critical: true, Timeout: time.Millisecond},
+			},
+			expectedStatus: health.StatusDown,
+			expectedCode:   http.StatusServiceUnavailable,
+		},
+		{
+			name:           "draining",
+			checks:         []health.Check{{Name: "db", Func: ok, Critical: true}},
+			draining:       true,
+			expectedStatus: health.StatusDown,
+			expectedCode:   http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			registry := health.NewRegistry()
+			for _, check := range testCase.checks {
+				if err := registry.Register(check); err != nil {
+					t.Fatalf("failed to register check: %s", err)
+				}
+			}
+
+			registry.SetDraining(testCase.draining)
+
+			w := httptest.NewRecorder()
+			registry.ReadinessHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if testCase.expectedCode != w.Code {
+				t.Errorf("expected code %d but got %d", testCase.expectedCode, w.Code)
+			}
+
+			var report health.Report
+			if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+				t.Fatalf("failed to decode report: %s", err)
+			}
+
+			if testCase.expectedStatus != report.Status {
+				t.Errorf("expected status '%s' but got '%s'", testCase.expectedStatus, report.Status)
+			}
+
+			if len(testCase.checks) != len(report.Checks) {
+				t.Errorf("expected %d checks in report but got %d", len(testCase.checks), len(report.Checks))
+			}
+		})
+	}
+}
+
+func TestRegistry_Liveness_Draining(t *testing.T) {
+	registry := health.NewRegistry()
+	registry.SetDraining(true)
+
+	report := registry.Liveness(context.Background())
+	if report.Status != health.StatusUp {
+		t.Errorf("expected draining service to be alive but got status '%s'", report.Status)
+	}
+}
+
+func TestRegistry_Liveness(t *testing.T) {
+	fail := func(_ context.Context) error { return fmt.Errorf("connection refused") }
+	ok := func(_ context.Context) error { return nil }
+
+	registry := health.NewRegistry()
+
+	for _, check := range []health.Check{
+		{Name: "db", Func: fail, Critical: true},
+		{Name: "deadlock", Func: ok, Critical: true, Liveness: true},
+	} {
+		if err := registry.Register(check); err != nil {
+			t.Fatalf("failed to register check: %s", err)
+		}
+	}
+
+	liveness := registry.Liveness(context.Background())
+	if liveness.Status != health.StatusUp {
+		t.Errorf("expected failing dependency not to affect liveness but got status '%s'", liveness.Status)
+	}
+
+	if len(liveness.Checks) != 1 || liveness.Checks[0].Name != "deadlock" {
+		t.Errorf("expected only the liveness check to be executed but got %v", liveness.Checks)
+	}
+
+	if readiness := registry.Readiness(context.Background()); readiness.Status != health.StatusDown {
+		t.Errorf("expected failing dependency to make the service unready but got status '%s'", readiness.Status)
+	}
+}
+
+func TestRegistry_Interval(t *testing.T) {
+	calls := 0
+
+	registry := health.NewRegistry()
+	if err := registry.Register(health.Check{
+		Name:     "db",
+		Interval: time.Hour,
+		Liveness: true,
+		Func: func(_ context.Context) error {
+			calls++
+			return nil
+		},
+	}); err != nil {
+		t.Fatalf("failed to register check: %s", err)
+	}
+
+	registry.Readiness(context.Background())
+	registry.Liveness(context.Background())
+
+	if calls != 1 {
+		t.Errorf("expected check to be executed once but was executed %d times", calls)
+	}
+}
@@ -0,0 +1,2 @@
+// Package health provides a registry of named health checks and handlers to expose liveness and readiness.
+package health
@@ -0,0 +1,78 @@
+package smis_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis"
+	"github.com/rebel-l/smis/health"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestService_RegisterHealthCheck(t *testing.T) {
+	service, err := smis.NewService(&http.Server{}, mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	err = service.RegisterHealthCheck(health.Check{
+		Name:     "db",
+		Critical: true,
+		Func:     func(_ context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("failed to register health check: %s", err)
+	}
+
+	if _, err = service.WithHealthEndpoints(); err != nil {
+		t.Errorf("initializing health endpoints twice should not fail but got: %s", err)
+	}
+
+	for _, path := range []string{smis.HealthPathLiveness, smis.HealthPathReadiness} {
+		w := httptest.NewRecorder()
+		service.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected code %d for %s but got %d", http.StatusOK, path, w.Code)
+		}
+	}
+}
+
+func TestService_Run_Draining(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, err := smis.NewService(newServerMock(ctrl), mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	if _, err = service.WithHealthEndpoints(); err != nil {
+		t.Fatalf("failed to initialize health endpoints: %s", err)
+	}
+
+	code := 0
+
+	service.OnShutdown("probe", 0, func(_ context.Context) error {
+		w := httptest.NewRecorder()
+		service.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, smis.HealthPathReadiness, nil))
+		code = w.Code
+
+		return nil
+	})
+
+	if err = service.Run(context.Background()); err != nil {
+		t.Fatalf("expected no error but got: %s", err)
+	}
+
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("expected readiness to fail with %d during shutdown but got %d", http.StatusServiceUnavailable, code)
+	}
+}
@@ -0,0 +1,144 @@
+package smis
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// HookStage defines at which point of the service lifecycle a hook is executed.
+type HookStage int
+
+const (
+	// HookStageBeforeListen hooks are executed in order of registration before the server starts listening.
+	// The first failing hook aborts the start.
+	HookStageBeforeListen HookStage = iota
+
+	// HookStageAfterListen hooks are executed in order of registration after the server started listening.
+	// A failing hook shuts the service down.
+	HookStageAfterListen
+
+	// HookStageBeforeShutdown hooks are executed in reverse order of registration before the server shuts down.
+	HookStageBeforeShutdown
+
+	// HookStageAfterShutdown hooks are executed in reverse order of registration after the server shut down.
+	// They are executed as well if the start fails, so they need to handle resources which weren't acquired.
+	HookStageAfterShutdown
+)
+
+// String returns a human readable name of the stage.
+func (h HookStage) String() string {
+	switch h {
+	case HookStageBeforeListen:
+		return "before listen"
+	case HookStageAfterListen:
+		return "after listen"
+	case HookStageBeforeShutdown:
+		return "before shutdown"
+	case HookStageAfterShutdown:
+		return "after shutdown"
+	default:
+		return fmt.Sprintf("unknown (%d)", int(h))
+	}
+}
+
+func (h HookStage) isTeardown() bool {
+	return h == HookStageBeforeShutdown || h == HookStageAfterShutdown
+}
+
+// HookFunc represents the callback executed by a lifecycle hook.
+type HookFunc func(ctx context.Context) error
+
+// Hook represents a named callback executed at a certain stage of the service lifecycle. If Timeout is greater than
+// zero, the context passed to the callback is canceled after the timeout and the hook fails.
+type Hook struct {
+	Name    string
+	Timeout time.Duration
+	Func    HookFunc
+}
+
+func (h Hook) execute(ctx context.Context) error {
+	if h.Func == nil {
+		return nil
+	}
+
+	if h.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.Timeout)
+
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- h.Func(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// AddHook adds a hook to the given stage of the service lifecycle. Hooks are only executed by Run().
+func (s *Service) AddHook(stage HookStage, hook Hook) {
+	if s.hooks == nil {
+		s.hooks = make(map[HookStage][]Hook)
+	}
+
+	s.hooks[stage] = append(s.hooks[stage], hook)
+}
+
+// OnStart adds a hook executed before the server starts listening, e.g. to open database pools.
+func (s *Service) OnStart(name string, timeout time.Duration, f HookFunc) {
+	s.AddHook(HookStageBeforeListen, Hook{Name: name, Timeout: timeout, Func: f})
+}
+
+// OnReady adds a hook executed after the server started listening, e.g. to warm up caches.
+func (s *Service) OnReady(name string, timeout time.Duration, f HookFunc) {
+	s.AddHook(HookStageAfterListen, Hook{Name: name, Timeout: timeout, Func: f})
+}
+
+// OnShutdown adds a hook executed before the server shuts down, e.g. to deregister from service discovery.
+func (s *Service) OnShutdown(name string, timeout time.Duration, f HookFunc) {
+	s.AddHook(HookStageBeforeShutdown, Hook{Name: name, Timeout: timeout, Func: f})
+}
+
+// OnStopped adds a hook executed after the server shut down or the start failed, e.g. to flush buffers or close
+// database pools.
+func (s *Service) OnStopped(name string, timeout time.Duration, f HookFunc) {
+	s.AddHook(HookStageAfterShutdown, Hook{Name: name, Timeout: timeout, Func: f})
+}
+
+// runHooks executes the hooks of a stage. Startup stages stop at the first failing hook, teardown stages execute
+// all hooks in reverse order and return the collected errors.
+func (s *Service) runHooks(ctx context.Context, stage HookStage) error {
+	hooks := s.hooks[stage]
+
+	var errs Errors
+
+	for i := range hooks {
+		hook := hooks[i]
+		if stage.isTeardown() {
+			hook = hooks[len(hooks)-1-i]
+		}
+
+		s.Log.Infof("Execute %s hook: %s", stage, hook.Name)
+
+		if err := hook.execute(ctx); err != nil {
+			err = fmt.Errorf("%s hook %s failed: %w", stage, hook.Name, err)
+			s.Log.Error(err)
+
+			if !stage.isTeardown() {
+				return err
+			}
+
+			errs = append(errs, err)
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
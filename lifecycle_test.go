@@ -0,0 +1,243 @@
+package smis_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis"
+	"github.com/rebel-l/smis/tests/mocks/smis_mock"
+
+	"github.com/sirupsen/logrus"
+)
+
+// shutdownServerMock combines the mocks of Server and ShutdownServer.
+type shutdownServerMock struct {
+	*smis_mock.MockServer
+	*smis_mock.MockShutdownServer
+}
+
+// newServerMock returns a server which stops by itself, so Run() doesn't need to be canceled.
+func newServerMock(ctrl *gomock.Controller) smis.Server {
+	serverMock := smis_mock.NewMockServer(ctrl)
+	serverMock.EXPECT().ListenAndServe().AnyTimes().Return(http.ErrServerClosed)
+
+	shutdownMock := smis_mock.NewMockShutdownServer(ctrl)
+	shutdownMock.EXPECT().Shutdown(gomock.Any()).AnyTimes().Return(nil)
+
+	return shutdownServerMock{MockServer: serverMock, MockShutdownServer: shutdownMock}
+}
+
+func TestService_Run_Hooks(t *testing.T) { // nolint: funlen
+	testCases := []struct {
+		name          string
+		failing       string
+		expectedOrder []string
+		expectedError string
+	}{
+		{
+			name: "success",
+			expectedOrder: []string{
+				"start 1", "start 2", "ready", "shutdown 2", "shutdown 1", "stopped 2", "stopped 1",
+			},
+		},
+		{
+			name:          "start fails",
+			failing:       "start 1",
+			expectedOrder: []string{"start 1", "stopped 2", "stopped 1"},
+			expectedError: "before listen hook start 1 failed: fail",
+		},
+		{
+			name:          "later start fails",
+			failing:       "start 2",
+			expectedOrder: []string{"start 1", "start 2", "stopped 2", "stopped 1"},
+			expectedError: "before listen hook start 2 failed: fail",
+		},
+		{
+			name:    "ready fails",
+			failing: "ready",
+			expectedOrder: []string{
+				"start 1", "start 2", "ready", "shutdown 2", "shutdown 1", "stopped 2", "stopped 1",
+			},
+			expectedError: "after listen hook ready failed: fail",
+		},
+		{
+			name:    "teardown continues on failure",
+			failing: "shutdown 2",
+			expectedOrder: []string{
+				"start 1", "start 2", "ready", "shutdown 2", "shutdown 1", "stopped 2", "stopped 1",
+			},
+			expectedError: "before shutdown hook shutdown 2 failed: fail",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, err := smis.NewService(newServerMock(ctrl), mux.NewRouter(), logrus.New())
+			if err != nil {
+				t.Fatalf("failed to create service: %s", err)
+			}
+
+			var order []string
+
+			hook := func(name string) smis.HookFunc {
+				return func(_ context.Context) error {
+					order = append(order, name)
+					if name == testCase.failing {
+						return fmt.Errorf("fail")
+					}
+
+					return nil
+				}
+			}
+
+			service.OnStart("start 1", 0, hook("start 1"))
+			service.OnStart("start 2", 0, hook("start 2"))
+			service.OnReady("ready", 0, hook("ready"))
+			service.OnShutdown("shutdown 1", 0, hook("shutdown 1"))
+			service.OnShutdown("shutdown 2", 0, hook("shutdown 2"))
+			service.OnStopped("stopped 1", 0, hook("stopped 1"))
+			service.OnStopped("stopped 2", 0, hook("stopped 2"))
+
+			err = service.Run(context.Background())
+			if testCase.expectedError == "" && err != nil {
+				t.Errorf("expected no error but got: %s", err)
+			}
+
+			if testCase.expectedError != "" && (err == nil || err.Error() != testCase.expectedError) {
+				t.Errorf("expected error '%s' but got '%v'", testCase.expectedError, err)
+			}
+
+			if !reflect.DeepEqual(testCase.expectedOrder, order) {
+				t.Errorf("expected hooks to be executed in order %v but got %v", testCase.expectedOrder, order)
+			}
+		})
+	}
+}
+
+func TestService_Run_HookTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, err := smis.NewService(newServerMock(ctrl), mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	service.OnStart("slow", time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+
+		return nil
+	})
+
+	err = service.Run(context.Background())
+	if err == nil || !strings.HasSuffix(err.Error(), context.DeadlineExceeded.Error()) {
+		t.Errorf("expected error to end with '%s' but got '%v'", context.DeadlineExceeded, err)
+	}
+}
+
+func TestService_Run_BindBeforeReady(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	defer func() {
+		_ = taken.Close()
+	}()
+
+	service, err := smis.NewService(&http.Server{Addr: taken.Addr().String()}, mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	var (
+		ready bool
+		order []string
+	)
+
+	service.OnStart("start", time.Second, func(_ context.Context) error {
+		order = append(order, "start")
+		return nil
+	})
+	service.OnReady("ready", time.Second, func(_ context.Context) error {
+		ready = true
+		return nil
+	})
+	service.OnStopped("stopped", time.Second, func(_ context.Context) error {
+		order = append(order, "stopped")
+		return nil
+	})
+
+	err = service.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "address already in use") {
+		t.Errorf("expected bind error but got: %v", err)
+	}
+
+	if ready {
+		t.Error("expected after listen hook not to run if the address can't be bound")
+	}
+
+	expectedOrder := []string{"start", "stopped"}
+	if !reflect.DeepEqual(expectedOrder, order) {
+		t.Errorf("expected hooks to be executed in order %v but got %v", expectedOrder, order)
+	}
+}
+
+func TestService_Run_ListeningWhenReady(t *testing.T) {
+	free, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	addr := free.Addr().String()
+	_ = free.Close()
+
+	service, err := smis.NewService(&http.Server{Addr: addr}, mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dialed := make(chan error, 1)
+
+	service.OnReady("dial", time.Second, func(_ context.Context) error {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			err = conn.Close()
+		}
+
+		dialed <- err
+
+		return nil
+	})
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- service.Run(ctx)
+	}()
+
+	if err = <-dialed; err != nil {
+		t.Errorf("expected server to listen when after listen hooks run but got: %s", err)
+	}
+
+	cancel()
+
+	if err = <-done; err != nil {
+		t.Errorf("expected no error but got: %s", err)
+	}
+}
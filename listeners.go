@@ -0,0 +1,175 @@
+package smis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gorilla/mux"
+)
+
+// AddListener adds a named listener with its own server and router, e.g. an admin port serving health and metrics
+// apart from the public API. The returned service is used to add the chains, middleware and routes of the listener,
+// it inherits the error handling settings, RequestID and ShutdownTimeout of this service. The listener is started and
+// shut down by Run() together with the main server, if one of them stops, all of them are shut down. Hooks are
+// executed only for the main service.
+func (s *Service) AddListener(name string, server Server, router *mux.Router) (*Service, error) {
+	if s.Listener(name) != nil {
+		return nil, fmt.Errorf("listener %s already exists", name)
+	}
+
+	listener, err := NewService(server, router, s.Log.WithField("listener", name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create listener %s: %w", name, err)
+	}
+
+	listener.UseProblemJSON = s.UseProblemJSON
+	listener.ErrorMapper = s.ErrorMapper
+	listener.RequestID = s.RequestID
+	listener.ShutdownTimeout = s.ShutdownTimeout
+	listener.listenerName = name
+
+	s.listeners = append(s.listeners, listener)
+
+	return listener, nil
+}
+
+// Listener returns the listener with the given name or nil if it doesn't exist.
+func (s *Service) Listener(name string) *Service {
+	for _, listener := range s.listeners {
+		if listener.listenerName == name {
+			return listener
+		}
+	}
+
+	return nil
+}
+
+// runningServer is a server started by Run(). The name is empty for the main server.
+type runningServer struct {
+	name    string
+	service *Service
+	err     error
+	stopped bool
+}
+
+func (r *runningServer) wrap(err error) error {
+	if err == nil || r.name == "" {
+		return err
+	}
+
+	return fmt.Errorf("listener %s: %w", r.name, err)
+}
+
+// serverGroup contains the main server and all listeners. Stopped servers are sent to done.
+type serverGroup struct {
+	servers []*runningServer
+	done    chan *runningServer
+}
+
+// bindServers binds the main server and all listeners, see bind(). If one fails, the listeners bound before are
+// closed.
+func (s *Service) bindServers() error {
+	services := append([]*Service{s}, s.listeners...)
+
+	for i, service := range services {
+		err := service.bind()
+		if err == nil {
+			continue
+		}
+
+		for _, bound := range services[:i] {
+			bound.closeBoundListener()
+		}
+
+		if service.listenerName != "" {
+			return fmt.Errorf("listener %s: %w", service.listenerName, err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) closeBoundListener() {
+	if s.boundListener != nil {
+		_ = s.boundListener.Close()
+		s.boundListener = nil
+	}
+}
+
+// startServers starts the main server and all listeners.
+func (s *Service) startServers() *serverGroup {
+	group := &serverGroup{
+		servers: []*runningServer{{service: s}},
+		done:    make(chan *runningServer, len(s.listeners)+1),
+	}
+
+	for _, listener := range s.listeners {
+		group.servers = append(group.servers, &runningServer{name: listener.listenerName, service: listener})
+	}
+
+	for _, server := range group.servers {
+		go func(server *runningServer) {
+			server.err = filterServerClosed(server.service.ListenAndServe())
+			group.done <- server
+		}(server)
+	}
+
+	return group
+}
+
+// shutdown shuts all servers down which are still running and waits for them to stop. It returns all errors of the
+// shutdown and of the servers.
+func (g *serverGroup) shutdown() Errors {
+	var (
+		result  Errors
+		mutex   sync.Mutex
+		wg      sync.WaitGroup
+		pending = make(map[*runningServer]bool)
+	)
+
+	for _, server := range g.servers {
+		if server.stopped {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(server *runningServer) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), server.service.getShutdownTimeout())
+			defer cancel()
+
+			err := server.service.Server.(ShutdownServer).Shutdown(ctx) // checked by Run()
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if err != nil {
+				if server.name == "" {
+					result = append(result, fmt.Errorf("failed to shutdown server gracefully: %w", err))
+				} else {
+					result = append(result, fmt.Errorf("failed to shutdown listener %s gracefully: %w", server.name, err))
+				}
+
+				return
+			}
+
+			pending[server] = true
+		}(server)
+	}
+
+	wg.Wait()
+
+	for len(pending) > 0 {
+		server := <-g.done
+		server.stopped = true
+		delete(pending, server)
+		result = result.Append(server.wrap(server.err))
+	}
+
+	return result
+}
@@ -0,0 +1,184 @@
+package smis_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis"
+
+	"github.com/sirupsen/logrus"
+)
+
+// blockingServerTest blocks in ListenAndServe until it is shut down or returns serveErr immediately if set.
+type blockingServerTest struct {
+	serveErr    error
+	shutdownErr error
+	closed      chan struct{}
+	once        sync.Once
+	mutex       sync.Mutex
+	shutdowns   int
+}
+
+func newBlockingServerTest(serveErr, shutdownErr error) *blockingServerTest {
+	return &blockingServerTest{serveErr: serveErr, shutdownErr: shutdownErr, closed: make(chan struct{})}
+}
+
+func (s *blockingServerTest) ListenAndServe() error {
+	if s.serveErr != nil {
+		return s.serveErr
+	}
+
+	<-s.closed
+
+	return http.ErrServerClosed
+}
+
+func (s *blockingServerTest) Shutdown(_ context.Context) error {
+	s.mutex.Lock()
+	s.shutdowns++
+	s.mutex.Unlock()
+
+	s.once.Do(func() {
+		close(s.closed)
+	})
+
+	return s.shutdownErr
+}
+
+func (s *blockingServerTest) getShutdowns() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.shutdowns
+}
+
+func TestService_AddListener(t *testing.T) {
+	service, err := smis.NewService(&http.Server{}, mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	service.UseProblemJSON = true
+
+	admin, err := service.AddListener("admin", &http.Server{}, mux.NewRouter())
+	if err != nil {
+		t.Fatalf("expected no error but got: %s", err)
+	}
+
+	if !admin.UseProblemJSON {
+		t.Error("expected listener to inherit UseProblemJSON")
+	}
+
+	if service.Listener("admin") != admin {
+		t.Error("expected to get the listener by name")
+	}
+
+	if service.Listener("unknown") != nil {
+		t.Error("expected nil for unknown listener")
+	}
+
+	if _, err = service.AddListener("admin", &http.Server{}, mux.NewRouter()); err == nil {
+		t.Error("expected an error for duplicate listener but got nil")
+	}
+
+	if _, err = service.AddListener("metrics", nil, mux.NewRouter()); err == nil {
+		t.Error("expected an error for missing server but got nil")
+	}
+}
+
+func TestService_Run_Listeners(t *testing.T) { // nolint: funlen
+	testCases := []struct {
+		name                string
+		cancel              bool
+		adminServeErr       error
+		adminShutdownErr    error
+		expectedErr         string
+		expectedMainStops   int
+		expectedAdminStops  int
+		expectedAdminDrains bool
+	}{
+		{
+			name:                "context canceled",
+			cancel:              true,
+			expectedMainStops:   1,
+			expectedAdminStops:  1,
+			expectedAdminDrains: true,
+		},
+		{
+			name:                "listener fails",
+			adminServeErr:       errors.New("address already in use"),
+			expectedErr:         "listener admin: address already in use",
+			expectedMainStops:   1,
+			expectedAdminDrains: true,
+		},
+		{
+			name:                "listener shutdown fails",
+			cancel:              true,
+			adminShutdownErr:    context.DeadlineExceeded,
+			expectedErr:         "failed to shutdown listener admin gracefully: context deadline exceeded",
+			expectedMainStops:   1,
+			expectedAdminStops:  1,
+			expectedAdminDrains: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mainServer := newBlockingServerTest(nil, nil)
+			adminServer := newBlockingServerTest(testCase.adminServeErr, testCase.adminShutdownErr)
+
+			service, err := smis.NewService(mainServer, mux.NewRouter(), logrus.New())
+			if err != nil {
+				t.Fatalf("failed to create service: %s", err)
+			}
+
+			service.ShutdownTimeout = time.Second
+
+			admin, err := service.AddListener("admin", adminServer, mux.NewRouter())
+			if err != nil {
+				t.Fatalf("failed to add listener: %s", err)
+			}
+
+			if _, err = admin.WithHealthEndpoints(); err != nil {
+				t.Fatalf("failed to add health endpoints: %s", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if testCase.cancel {
+				cancel()
+			} else {
+				defer cancel()
+			}
+
+			err = service.Run(ctx)
+
+			if testCase.expectedErr == "" && err != nil {
+				t.Errorf("expected no error but got: %s", err)
+			}
+
+			if testCase.expectedErr != "" && (err == nil || testCase.expectedErr != err.Error()) {
+				t.Errorf("expected error '%s' but got '%v'", testCase.expectedErr, err)
+			}
+
+			if testCase.expectedMainStops != mainServer.getShutdowns() {
+				t.Errorf("expected main server to be shut down %d times but got %d",
+					testCase.expectedMainStops, mainServer.getShutdowns())
+			}
+
+			if testCase.expectedAdminStops != adminServer.getShutdowns() {
+				t.Errorf("expected admin server to be shut down %d times but got %d",
+					testCase.expectedAdminStops, adminServer.getShutdowns())
+			}
+
+			if testCase.expectedAdminDrains != admin.Health.IsDraining() {
+				t.Errorf("expected admin draining to be %t", testCase.expectedAdminDrains)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package smis
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis/metrics"
+)
+
+// MetricsPath is the path of the metrics endpoint
+const MetricsPath = "/metrics"
+
+// WithMetrics initializes the metrics registry, records the metrics of all chains and registers the endpoint serving
+// them in Prometheus text exposition format at the default chain. Calling it multiple times has no effect.
+func (s *Service) WithMetrics() (*Service, error) {
+	if s.Metrics != nil {
+		return s, nil
+	}
+
+	registry := metrics.NewRegistry()
+
+	if _, err := s.RegisterEndpoint(MetricsPath, http.MethodGet, registry.Handler()); err != nil {
+		return s, err
+	}
+
+	// middleware of the main router is executed for the routes of all chains
+	mw := registry.Middleware(s.getChain)
+	s.AddMiddlewareForDefaultChain(mw)
+	s.Router.NotFoundHandler = mw(s.Router.NotFoundHandler)
+	s.Router.MethodNotAllowedHandler = mw(s.Router.MethodNotAllowedHandler)
+
+	s.Metrics = registry
+
+	return s, nil
+}
+
+// getChain returns the chain the route matching the request belongs to.
+func (s *Service) getChain(request *http.Request) string {
+	route := mux.CurrentRoute(request)
+	if route == nil {
+		return MiddlewareChainDefault
+	}
+
+	return s.chainOfRoute(route)
+}
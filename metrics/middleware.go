@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis/middleware/writer"
+)
+
+const (
+	// RouteUnmatched is the route label for requests which didn't match any route
+	RouteUnmatched = "unmatched"
+
+	// MethodOther is the method label for requests with a method which isn't a standard HTTP method
+	MethodOther = "other"
+)
+
+// ChainFunc returns the middleware chain a request belongs to.
+type ChainFunc func(request *http.Request) string
+
+// Middleware returns a middleware recording count, duration and in-flight requests. The route label is the path
+// template of the matched route, so paths with variables don't create new series. The same applies to the method
+// label, non-standard methods are recorded as MethodOther.
+func (r *Registry) Middleware(chain ChainFunc) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+			chainName := chain(request)
+			route := getRoute(request)
+			method := getMethod(request)
+
+			r.inFlight.Inc(chainName)
+			defer r.inFlight.Dec(chainName)
+
+			start := time.Now()
+			recorder := writer.NewRecorder(w)
+			panicked := true
+
+			// deferred to count panicking requests too, the recovery middleware responds them with 500
+			defer func() {
+				status := recorder.Status()
+				if panicked {
+					status = http.StatusInternalServerError
+				}
+
+				r.duration.Observe(time.Since(start).Seconds(), chainName, route, method)
+				r.requests.Inc(chainName, route, method, statusClass(status))
+			}()
+
+			next.ServeHTTP(recorder, request)
+
+			panicked = false
+		})
+	}
+}
+
+func getRoute(request *http.Request) string {
+	if route := mux.CurrentRoute(request); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+
+	return RouteUnmatched
+}
+
+// getMethod returns the method of the request if it is one of the methods allowed by smis, otherwise MethodOther.
+func getMethod(request *http.Request) string {
+	switch request.Method {
+	case http.MethodConnect, http.MethodDelete, http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPatch,
+		http.MethodPost, http.MethodPut, http.MethodTrace:
+		return request.Method
+	default:
+		return MethodOther
+	}
+}
+
+func statusClass(status int) string {
+	return fmt.Sprintf("%dxx", status/100)
+}
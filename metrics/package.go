@@ -0,0 +1,3 @@
+// Package metrics provides counters, gauges and histograms with labels, a middleware recording HTTP request metrics
+// and a handler serving them in the Prometheus text exposition format.
+package metrics
@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+const (
+	// ContentType is the content type of the Prometheus text exposition format
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+	// MetricRequestsTotal is the name of the counter of handled requests
+	MetricRequestsTotal = "http_requests_total"
+
+	// MetricRequestDuration is the name of the histogram of request durations in seconds
+	MetricRequestDuration = "http_request_duration_seconds"
+
+	// MetricRequestsInFlight is the name of the gauge of requests currently handled
+	MetricRequestsInFlight = "http_requests_in_flight"
+
+	// LabelChain is the label containing the middleware chain
+	LabelChain = "chain"
+
+	// LabelRoute is the label containing the route template
+	LabelRoute = "route"
+
+	// LabelMethod is the label containing the request method
+	LabelMethod = "method"
+
+	// LabelStatus is the label containing the status class of the response, e.g. 2xx
+	LabelStatus = "status"
+)
+
+// DefaultBuckets returns the default upper bounds of the request duration histogram in seconds.
+func DefaultBuckets() []float64 {
+	return []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+}
+
+// Registry holds the collectors to expose. It contains the HTTP request metrics recorded by the middleware.
+type Registry struct {
+	mutex      sync.RWMutex
+	collectors map[string]Collector
+
+	requests *CounterVec
+	duration *HistogramVec
+	inFlight *GaugeVec
+}
+
+// NewRegistry returns a registry containing the HTTP request metrics.
+func NewRegistry() *Registry {
+	r := &Registry{
+		collectors: make(map[string]Collector),
+		requests: NewCounterVec(
+			MetricRequestsTotal,
+			"Total number of handled HTTP requests.",
+			LabelChain, LabelRoute, LabelMethod, LabelStatus,
+		),
+		duration: NewHistogramVec(
+			MetricRequestDuration,
+			"Duration of HTTP requests in seconds.",
+			DefaultBuckets(),
+			LabelChain, LabelRoute, LabelMethod,
+		),
+		inFlight: NewGaugeVec(
+			MetricRequestsInFlight,
+			"Number of HTTP requests currently handled.",
+			LabelChain,
+		),
+	}
+
+	for _, c := range []Collector{r.requests, r.duration, r.inFlight} {
+		r.collectors[c.Name()] = c
+	}
+
+	return r
+}
+
+// Register adds a collector to the registry. The name of a collector must be unique.
+func (r *Registry) Register(collector Collector) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.collectors[collector.Name()]; ok {
+		return fmt.Errorf("metric %s is already registered", collector.Name())
+	}
+
+	r.collectors[collector.Name()] = collector
+
+	return nil
+}
+
+// Write writes all collectors sorted by name in Prometheus text exposition format.
+func (r *Registry) Write(w io.Writer) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := r.collectors[name].Write(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Handler returns a handler serving all collectors in Prometheus text exposition format.
+func (r *Registry) Handler() http.HandlerFunc {
+	return func(writer http.ResponseWriter, _ *http.Request) {
+		var buffer bytes.Buffer
+		if err := r.Write(&buffer); err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		writer.Header().Set("Content-Type", ContentType)
+		_, _ = buffer.WriteTo(writer)
+	}
+}
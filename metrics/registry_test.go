@@ -0,0 +1,124 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis/metrics"
+	"github.com/rebel-l/smis/middleware/recovery"
+
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestRegistry_Register(t *testing.T) {
+	registry := metrics.NewRegistry()
+
+	if err := registry.Register(metrics.NewCounterVec("jobs_total", "Total jobs.")); err != nil {
+		t.Errorf("expected no error but got: %s", err)
+	}
+
+	err := registry.Register(metrics.NewCounterVec(metrics.MetricRequestsTotal, "Duplicate."))
+	if err == nil || err.Error() != "metric http_requests_total is already registered" {
+		t.Errorf("expected error for duplicate metric but got '%v'", err)
+	}
+}
+
+func TestRegistry_Middleware(t *testing.T) {
+	registry := metrics.NewRegistry()
+
+	router := mux.NewRouter()
+	router.Use(registry.Middleware(func(_ *http.Request) string { return "public" }))
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	router.HandleFunc("/metrics", registry.Handler())
+
+	for _, path := range []string{"/user/1", "/user/2"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if contentType := w.Header().Get("Content-Type"); contentType != metrics.ContentType {
+		t.Errorf("expected content type '%s' but got '%s'", metrics.ContentType, contentType)
+	}
+
+	expectedLines := []string{
+		`http_requests_total{chain="public",route="/user/{id}",method="GET",status="4xx"} 2`,
+		`http_request_duration_seconds_count{chain="public",route="/user/{id}",method="GET"} 2`,
+		`http_requests_in_flight{chain="public"} 1`,
+	}
+
+	for _, line := range expectedLines {
+		if !strings.Contains(w.Body.String(), line+"\n") {
+			t.Errorf("expected output to contain '%s' but got\n%s", line, w.Body.String())
+		}
+	}
+}
+
+func TestRegistry_Middleware_Panic(t *testing.T) {
+	registry := metrics.NewRegistry()
+	log, _ := test.NewNullLogger()
+
+	router := mux.NewRouter()
+	router.Use(recovery.New(log), registry.Middleware(func(_ *http.Request) string { return "default" }))
+	router.HandleFunc("/panic", func(_ http.ResponseWriter, _ *http.Request) {
+		panic("something went wrong")
+	})
+	router.HandleFunc("/metrics", registry.Handler())
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	expectedLines := []string{
+		`http_requests_total{chain="default",route="/panic",method="GET",status="5xx"} 1`,
+		`http_request_duration_seconds_count{chain="default",route="/panic",method="GET"} 1`,
+	}
+
+	for _, line := range expectedLines {
+		if !strings.Contains(w.Body.String(), line+"\n") {
+			t.Errorf("expected output to contain '%s' but got\n%s", line, w.Body.String())
+		}
+	}
+}
+
+func TestRegistry_Middleware_UnknownMethod(t *testing.T) {
+	registry := metrics.NewRegistry()
+
+	middleware := registry.Middleware(func(_ *http.Request) string { return "default" })
+
+	router := mux.NewRouter()
+	router.NotFoundHandler = middleware(http.NotFoundHandler())
+	router.HandleFunc("/metrics", registry.Handler())
+
+	for _, method := range []string{"AAA", "BBB", http.MethodGet} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/x", nil))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	expectedLines := []string{
+		`http_requests_total{chain="default",route="unmatched",method="other",status="4xx"} 2`,
+		`http_requests_total{chain="default",route="unmatched",method="GET",status="4xx"} 1`,
+	}
+
+	for _, line := range expectedLines {
+		if !strings.Contains(w.Body.String(), line+"\n") {
+			t.Errorf("expected output to contain '%s' but got\n%s", line, w.Body.String())
+		}
+	}
+
+	for _, method := range []string{"AAA", "BBB"} {
+		if strings.Contains(w.Body.String(), `method="`+method+`"`) {
+			t.Errorf("expected method %s not to be used as label but got\n%s", method, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	typeCounter   = "counter"
+	typeGauge     = "gauge"
+	typeHistogram = "histogram"
+)
+
+// Collector is a metric which can be exposed by the Registry.
+type Collector interface {
+	Name() string
+	Write(w io.Writer) error
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+	buckets     []uint64
+	count       uint64
+}
+
+type vec struct {
+	name       string
+	help       string
+	metricType string
+	labelNames []string
+	buckets    []float64
+	mutex      sync.Mutex
+	series     map[string]*series
+}
+
+func newVec(metricType, name, help string, labelNames []string) *vec {
+	return &vec{
+		name:       name,
+		help:       help,
+		metricType: metricType,
+		labelNames: labelNames,
+		series:     make(map[string]*series),
+	}
+}
+
+// Name returns the name of the metric.
+func (v *vec) Name() string {
+	return v.name
+}
+
+// get returns the series for the label values, the caller must hold the lock.
+func (v *vec) get(labelValues []string) *series {
+	if len(labelValues) != len(v.labelNames) {
+		panic(fmt.Sprintf(
+			"metric %s expects %d label values but got %d", v.name, len(v.labelNames), len(labelValues),
+		))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	s, ok := v.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		if v.metricType == typeHistogram {
+			s.buckets = make([]uint64, len(v.buckets))
+		}
+
+		v.series[key] = s
+	}
+
+	return s
+}
+
+// Write writes the metric in Prometheus text exposition format.
+func (v *vec) Write(w io.Writer) error {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", v.name, escapeHelp(v.help), v.name, v.metricType)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(v.series))
+	for key := range v.series {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := v.writeSeries(w, v.series[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *vec) writeSeries(w io.Writer, s *series) error {
+	labels := formatLabels(v.labelNames, s.labelValues)
+
+	if v.metricType != typeHistogram {
+		_, err := fmt.Fprintf(w, "%s%s %s\n", v.name, wrapLabels(labels), formatFloat(s.value))
+		return err
+	}
+
+	for i, upperBound := range v.buckets {
+		le := formatLabels([]string{"le"}, []string{formatFloat(upperBound)})
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", v.name, wrapLabels(labels, le), s.buckets[i]); err != nil {
+			return err
+		}
+	}
+
+	le := formatLabels([]string{"le"}, []string{"+Inf"})
+	if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", v.name, wrapLabels(labels, le), s.count); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "%s_sum%s %s\n", v.name, wrapLabels(labels), formatFloat(s.value)); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "%s_count%s %d\n", v.name, wrapLabels(labels), s.count)
+
+	return err
+}
+
+// CounterVec is a counter partitioned by labels.
+type CounterVec struct {
+	*vec
+}
+
+// NewCounterVec returns a counter with the given label names.
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{vec: newVec(typeCounter, name, help, labelNames)}
+}
+
+// Inc increments the counter for the label values by one.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increases the counter for the label values. Negative values are ignored as counters only go up.
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.get(labelValues).value += value
+}
+
+// GaugeVec is a gauge partitioned by labels.
+type GaugeVec struct {
+	*vec
+}
+
+// NewGaugeVec returns a gauge with the given label names.
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{vec: newVec(typeGauge, name, help, labelNames)}
+}
+
+// Inc increments the gauge for the label values by one.
+func (g *GaugeVec) Inc(labelValues ...string) {
+	g.Add(1, labelValues...)
+}
+
+// Dec decrements the gauge for the label values by one.
+func (g *GaugeVec) Dec(labelValues ...string) {
+	g.Add(-1, labelValues...)
+}
+
+// Add adds the value to the gauge for the label values.
+func (g *GaugeVec) Add(value float64, labelValues ...string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.get(labelValues).value += value
+}
+
+// Set sets the gauge for the label values.
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.get(labelValues).value = value
+}
+
+// HistogramVec is a histogram partitioned by labels.
+type HistogramVec struct {
+	*vec
+}
+
+// NewHistogramVec returns a histogram with the given upper bounds of the buckets and label names.
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	v := newVec(typeHistogram, name, help, labelNames)
+	v.buckets = append([]float64(nil), buckets...)
+	sort.Float64s(v.buckets)
+
+	return &HistogramVec{vec: v}
+}
+
+// Observe adds a single observation to the histogram for the label values.
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	s := h.get(labelValues)
+	s.value += value
+	s.count++
+
+	for i, upperBound := range h.buckets {
+		if value <= upperBound {
+			s.buckets[i]++
+		}
+	}
+}
+
+func formatLabels(names, values []string) string {
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf(`%s="%s"`, name, escapeLabelValue(values[i]))
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func wrapLabels(labels ...string) string {
+	var nonEmpty []string
+
+	for _, l := range labels {
+		if l != "" {
+			nonEmpty = append(nonEmpty, l)
+		}
+	}
+
+	if len(nonEmpty) == 0 {
+		return ""
+	}
+
+	return "{" + strings.Join(nonEmpty, ",") + "}"
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
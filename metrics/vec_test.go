@@ -0,0 +1,81 @@
+package metrics_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rebel-l/smis/metrics"
+)
+
+func TestVec_Write(t *testing.T) { // nolint: funlen
+	counter := metrics.NewCounterVec("jobs_total", "Total jobs.", "queue")
+	counter.Inc("mail")
+	counter.Add(2, "mail")
+	counter.Add(-1, "mail")
+	counter.Inc(`pdf "export"`)
+
+	gauge := metrics.NewGaugeVec("workers", "Active workers.")
+	gauge.Set(5)
+	gauge.Dec()
+
+	histogram := metrics.NewHistogramVec("job_seconds", "Job duration.", []float64{1, 0.5}, "queue")
+	histogram.Observe(0.25, "mail")
+	histogram.Observe(0.75, "mail")
+	histogram.Observe(3, "mail")
+
+	testCases := []struct {
+		name      string
+		collector metrics.Collector
+		expected  string
+	}{
+		{
+			name:      "counter",
+			collector: counter,
+			expected: "# HELP jobs_total Total jobs.\n" +
+				"# TYPE jobs_total counter\n" +
+				"jobs_total{queue=\"mail\"} 3\n" +
+				"jobs_total{queue=\"pdf \\\"export\\\"\"} 1\n",
+		},
+		{
+			name:      "gauge without labels",
+			collector: gauge,
+			expected: "# HELP workers Active workers.\n" +
+				"# TYPE workers gauge\n" +
+				"workers 4\n",
+		},
+		{
+			name:      "histogram",
+			collector: histogram,
+			expected: "# HELP job_seconds Job duration.\n" +
+				"# TYPE job_seconds histogram\n" +
+				"job_seconds_bucket{queue=\"mail\",le=\"0.5\"} 1\n" +
+				"job_seconds_bucket{queue=\"mail\",le=\"1\"} 2\n" +
+				"job_seconds_bucket{queue=\"mail\",le=\"+Inf\"} 3\n" +
+				"job_seconds_sum{queue=\"mail\"} 4\n" +
+				"job_seconds_count{queue=\"mail\"} 3\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			if err := testCase.collector.Write(&buffer); err != nil {
+				t.Fatalf("failed to write metric: %s", err)
+			}
+
+			if testCase.expected != buffer.String() {
+				t.Errorf("expected output\n%s\nbut got\n%s", testCase.expected, buffer.String())
+			}
+		})
+	}
+}
+
+func TestVec_WrongLabelCount(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for wrong number of label values")
+		}
+	}()
+
+	metrics.NewCounterVec("jobs_total", "Total jobs.", "queue").Inc()
+}
@@ -0,0 +1,64 @@
+package smis_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestService_WithMetrics(t *testing.T) {
+	service, err := smis.NewService(&http.Server{}, mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	if _, err = service.WithMetrics(); err != nil {
+		t.Fatalf("failed to initialize metrics: %s", err)
+	}
+
+	if _, err = service.WithMetrics(); err != nil {
+		t.Errorf("initializing metrics twice should not fail but got: %s", err)
+	}
+
+	handler := func(_ http.ResponseWriter, _ *http.Request) {}
+
+	// registered before the public chain exists, so it is matched first but belongs to the default chain
+	if _, err = service.RegisterEndpoint("/public/status", http.MethodGet, handler); err != nil {
+		t.Fatalf("failed to register endpoint: %s", err)
+	}
+
+	if _, err = service.RegisterEndpointToPublicChain("/user/{id}", http.MethodGet, handler); err != nil {
+		t.Fatalf("failed to register endpoint: %s", err)
+	}
+
+	if _, err = service.RegisterEndpoint("/ping", http.MethodGet, handler); err != nil {
+		t.Fatalf("failed to register endpoint: %s", err)
+	}
+
+	for _, path := range []string{"/public/user/1", "/public/status", "/ping", "/unknown"} {
+		service.Router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	service.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, smis.MetricsPath, nil))
+
+	expectedLines := []string{
+		`http_requests_total{chain="public",route="/public/user/{id}",method="GET",status="2xx"} 1`,
+		`http_requests_total{chain="default",route="/public/status",method="GET",status="2xx"} 1`,
+		`http_requests_total{chain="default",route="/ping",method="GET",status="2xx"} 1`,
+		`http_requests_total{chain="default",route="unmatched",method="GET",status="4xx"} 1`,
+	}
+
+	for _, line := range expectedLines {
+		if !strings.Contains(w.Body.String(), line+"\n") {
+			t.Errorf("expected output to contain '%s' but got\n%s", line, w.Body.String())
+		}
+	}
+}
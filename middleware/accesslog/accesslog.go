@@ -0,0 +1,151 @@
+package accesslog
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis/middleware/requestid"
+	"github.com/rebel-l/smis/middleware/writer"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// LogFieldMethod is the key of the log field containing the request method
+	LogFieldMethod = "method"
+
+	// LogFieldPath is the key of the log field containing the request path
+	LogFieldPath = "path"
+
+	// LogFieldStatus is the key of the log field containing the response status code
+	LogFieldStatus = "status"
+
+	// LogFieldSize is the key of the log field containing the response size in bytes
+	LogFieldSize = "size"
+
+	// LogFieldDuration is the key of the log field containing the duration of the request
+	LogFieldDuration = "duration"
+
+	// LogFieldRemoteAddr is the key of the log field containing the address of the client
+	LogFieldRemoteAddr = "remote_addr"
+
+	// LogFieldUserAgent is the key of the log field containing the user agent of the client
+	LogFieldUserAgent = "user_agent"
+
+	// MessageStructured is the log message used for FormatStructured
+	MessageStructured = "Request handled"
+
+	timeFormatCommon = "02/Jan/2006:15:04:05 -0700"
+)
+
+type accessLog struct {
+	Config Config
+	Log    logrus.FieldLogger
+}
+
+// New returns a middleware logging every request which path is not excluded. Put it behind the requestid middleware
+// to get the request ID logged.
+func New(log logrus.FieldLogger, config Config) mux.MiddlewareFunc {
+	mw := &accessLog{Config: config, Log: log}
+	return mw.handler
+}
+
+func (a *accessLog) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		if a.Config.isExcluded(request.URL.Path) {
+			next.ServeHTTP(w, request)
+			return
+		}
+
+		start := time.Now()
+		recorder := writer.NewRecorder(w)
+		panicked := true
+
+		// deferred to log panicking requests too, the recovery middleware responds them with 500
+		defer func() {
+			status := recorder.Status()
+			if panicked {
+				status = http.StatusInternalServerError
+			}
+
+			a.log(request, recorder, status, start)
+		}()
+
+		next.ServeHTTP(recorder, request)
+
+		panicked = false
+	})
+}
+
+func (a *accessLog) log(request *http.Request, recorder *writer.Recorder, status int, start time.Time) {
+	log := requestid.NewLoggerFromContext(request.Context(), a.Log)
+
+	switch a.Config.Format {
+	case FormatCommon:
+		log.Info(common(request, recorder, status, start))
+	case FormatCombined:
+		log.Info(combined(request, recorder, status, start))
+	default:
+		log.WithFields(logrus.Fields{
+			LogFieldMethod:     request.Method,
+			LogFieldPath:       request.URL.Path,
+			LogFieldStatus:     status,
+			LogFieldSize:       recorder.Size(),
+			LogFieldDuration:   time.Since(start).String(),
+			LogFieldRemoteAddr: request.RemoteAddr,
+			LogFieldUserAgent:  request.UserAgent(),
+		}).Info(MessageStructured)
+	}
+}
+
+// common returns the line in Apache common log format: %h %l %u %t "%r" %>s %b
+func common(request *http.Request, recorder *writer.Recorder, status int, start time.Time) string {
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		host = request.RemoteAddr
+	}
+
+	user := "-"
+	if u, _, ok := request.BasicAuth(); ok && u != "" {
+		user = u
+	}
+
+	size := "-"
+	if recorder.Size() > 0 {
+		size = fmt.Sprint(recorder.Size())
+	}
+
+	return fmt.Sprintf(
+		`%s - %s [%s] "%s %s %s" %d %s`,
+		host,
+		user,
+		start.Format(timeFormatCommon),
+		request.Method,
+		request.RequestURI,
+		request.Proto,
+		status,
+		size,
+	)
+}
+
+// combined returns the line in Apache combined log format: %h %l %u %t "%r" %>s %b "%{Referer}i" "%{User-agent}i"
+func combined(request *http.Request, recorder *writer.Recorder, status int, start time.Time) string {
+	return fmt.Sprintf(
+		`%s "%s" "%s"`,
+		common(request, recorder, status, start),
+		orDash(request.Referer()),
+		orDash(request.UserAgent()),
+	)
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
@@ -0,0 +1,135 @@
+package accesslog_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/rebel-l/go-utils/slice"
+
+	"github.com/rebel-l/smis/middleware/accesslog"
+	"github.com/rebel-l/smis/middleware/recovery"
+	"github.com/rebel-l/smis/middleware/requestid"
+
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestNew(t *testing.T) { // nolint: funlen
+	testCases := []struct {
+		name            string
+		config          accesslog.Config
+		path            string
+		expectedEntries int
+		expectedMessage *regexp.Regexp
+	}{
+		{
+			name:            "structured",
+			path:            "/user/1?expand=true",
+			expectedEntries: 1,
+			expectedMessage: regexp.MustCompile("^" + accesslog.MessageStructured + "$"),
+		},
+		{
+			name:            "common",
+			config:          accesslog.Config{Format: accesslog.FormatCommon},
+			path:            "/user/1?expand=true",
+			expectedEntries: 1,
+			expectedMessage: regexp.MustCompile(
+				`^192\.0\.2\.1 - - \[.+] "POST /user/1\?expand=true HTTP/1\.1" 201 7$`,
+			),
+		},
+		{
+			name:            "combined",
+			config:          accesslog.Config{Format: accesslog.FormatCombined},
+			path:            "/user/1",
+			expectedEntries: 1,
+			expectedMessage: regexp.MustCompile(
+				`^192\.0\.2\.1 - - \[.+] "POST /user/1 HTTP/1\.1" 201 7 "http://example\.com" "smis-test"$`,
+			),
+		},
+		{
+			name:   "excluded",
+			config: accesslog.Config{ExcludePaths: slice.StringSlice{"/healthz"}},
+			path:   "/healthz",
+		},
+		{
+			name:   "excluded by prefix",
+			config: accesslog.Config{ExcludePaths: slice.StringSlice{"/static/*"}},
+			path:   "/static/app.js",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			log, hook := test.NewNullLogger()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte("created"))
+			})
+
+			request := httptest.NewRequest(http.MethodPost, testCase.path, nil)
+			request.Header.Set("Referer", "http://example.com")
+			request.Header.Set("User-Agent", "smis-test")
+			request = request.WithContext(context.WithValue(request.Context(), requestid.ContextKeyRequestID, "abc"))
+
+			accesslog.New(log, testCase.config).Middleware(next).ServeHTTP(httptest.NewRecorder(), request)
+
+			entries := hook.AllEntries()
+			if testCase.expectedEntries != len(entries) {
+				t.Fatalf("expected %d log entries but got %d", testCase.expectedEntries, len(entries))
+			}
+
+			if testCase.expectedEntries == 0 {
+				return
+			}
+
+			entry := entries[0]
+			if !testCase.expectedMessage.MatchString(entry.Message) {
+				t.Errorf("expected message to match '%s' but got '%s'", testCase.expectedMessage, entry.Message)
+			}
+
+			if entry.Data[string(requestid.ContextKeyRequestID)] != "abc" {
+				t.Errorf("expected request ID 'abc' but got '%v'", entry.Data[string(requestid.ContextKeyRequestID)])
+			}
+
+			if testCase.config.Format != "" {
+				return
+			}
+
+			if entry.Data[accesslog.LogFieldStatus] != http.StatusCreated {
+				t.Errorf("expected status %d but got '%v'", http.StatusCreated, entry.Data[accesslog.LogFieldStatus])
+			}
+
+			if entry.Data[accesslog.LogFieldSize] != 7 {
+				t.Errorf("expected size 7 but got '%v'", entry.Data[accesslog.LogFieldSize])
+			}
+
+			if entry.Data[accesslog.LogFieldPath] != "/user/1" {
+				t.Errorf("expected path '/user/1' but got '%v'", entry.Data[accesslog.LogFieldPath])
+			}
+		})
+	}
+}
+
+func TestNew_Panic(t *testing.T) {
+	log, hook := test.NewNullLogger()
+
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("something went wrong")
+	})
+
+	mw := recovery.New(log)(accesslog.New(log, accesslog.Config{Format: accesslog.FormatCommon})(handler))
+	mw.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	expected := regexp.MustCompile(`^192\.0\.2\.1 - - \[.+] "GET /panic HTTP/1\.1" 500 -$`)
+
+	for _, entry := range hook.AllEntries() {
+		if expected.MatchString(entry.Message) {
+			return
+		}
+	}
+
+	t.Errorf("expected access log entry with status 500 but got %d entries: %v", len(hook.AllEntries()), hook.AllEntries())
+}
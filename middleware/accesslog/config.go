@@ -0,0 +1,46 @@
+package accesslog
+
+import (
+	"strings"
+
+	"github.com/rebel-l/go-utils/slice"
+)
+
+const (
+	// FormatStructured logs the request details as fields of the log entry
+	FormatStructured Format = "structured"
+
+	// FormatCommon logs the request in the Apache common log format
+	FormatCommon Format = "common"
+
+	// FormatCombined logs the request in the Apache combined log format
+	FormatCombined Format = "combined"
+)
+
+// Format defines how a request is logged.
+type Format string
+
+// Config provides a configuration for the access log middleware. Requests to paths listed in ExcludePaths are not
+// logged, a path ending with * excludes all paths starting with it. Format defaults to FormatStructured.
+type Config struct {
+	Format       Format            `json:"format,omitempty"`
+	ExcludePaths slice.StringSlice `json:"exclude_paths,omitempty"`
+}
+
+func (c Config) isExcluded(path string) bool {
+	for _, p := range c.ExcludePaths {
+		if strings.HasSuffix(p, "*") {
+			if strings.HasPrefix(path, strings.TrimSuffix(p, "*")) {
+				return true
+			}
+
+			continue
+		}
+
+		if p == path {
+			return true
+		}
+	}
+
+	return false
+}
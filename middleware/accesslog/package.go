@@ -0,0 +1,3 @@
+// Package accesslog provides a middleware which logs one line per request containing method, path, status code,
+// size and duration of the response.
+package accesslog
@@ -0,0 +1,95 @@
+package clientcert
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis/middleware/requestid"
+
+	"github.com/sirupsen/logrus"
+)
+
+type contextKey string
+
+const (
+	// ContextKeyIdentity is the key in the context where to find the client identity
+	ContextKeyIdentity contextKey = "clientIdentity"
+
+	// ErrorMessage is the message sent to the client if no verified client certificate was presented
+	ErrorMessage = "client certificate required"
+)
+
+// Identity is the identity of a client taken from its verified certificate.
+type Identity struct {
+	CommonName     string   `json:"common_name"`
+	Organization   []string `json:"organization,omitempty"`
+	DNSNames       []string `json:"dns_names,omitempty"`
+	EmailAddresses []string `json:"email_addresses,omitempty"`
+	URIs           []string `json:"uris,omitempty"`
+	SerialNumber   string   `json:"serial_number"`
+	Issuer         string   `json:"issuer"`
+}
+
+// NewIdentity returns the identity of the certificate.
+func NewIdentity(certificate *x509.Certificate) *Identity {
+	identity := &Identity{
+		CommonName:     certificate.Subject.CommonName,
+		Organization:   certificate.Subject.Organization,
+		DNSNames:       certificate.DNSNames,
+		EmailAddresses: certificate.EmailAddresses,
+		SerialNumber:   certificate.SerialNumber.String(),
+		Issuer:         certificate.Issuer.String(),
+	}
+
+	for _, uri := range certificate.URIs {
+		identity.URIs = append(identity.URIs, uri.String())
+	}
+
+	return identity
+}
+
+// GetIdentity returns the client identity set to the context. Is nil if the context doesn't contain any identity.
+func GetIdentity(ctx context.Context) *Identity {
+	identity, _ := ctx.Value(ContextKeyIdentity).(*Identity)
+	return identity
+}
+
+type clientCert struct {
+	Log logrus.FieldLogger
+}
+
+type errorJSON struct {
+	Error string `json:"error"`
+}
+
+// New returns a middleware adding the identity of the verified client certificate to the context. Requests without
+// verified client certificate are rejected with 401. The server needs to verify client certificates, see tlsconfig.
+func New(log logrus.FieldLogger) mux.MiddlewareFunc {
+	mw := &clientCert{Log: log}
+	return mw.handler
+}
+
+func (c *clientCert) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.TLS == nil || len(request.TLS.VerifiedChains) == 0 || len(request.TLS.VerifiedChains[0]) == 0 {
+			requestid.NewLoggerFromContext(request.Context(), c.Log).Warn("request without verified client certificate")
+
+			writer.Header().Set("Content-Type", "application/json")
+			writer.WriteHeader(http.StatusUnauthorized)
+
+			response, _ := json.Marshal(errorJSON{Error: ErrorMessage})
+			_, _ = writer.Write(response)
+
+			return
+		}
+
+		identity := NewIdentity(request.TLS.VerifiedChains[0][0])
+		ctx := context.WithValue(request.Context(), ContextKeyIdentity, identity)
+
+		next.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
@@ -0,0 +1,96 @@
+package clientcert_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/rebel-l/smis/middleware/clientcert"
+
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestNew(t *testing.T) { // nolint: funlen
+	uri, _ := url.Parse("spiffe://example.org/billing")
+	certificate := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "billing", Organization: []string{"acme"}},
+		Issuer:         pkix.Name{CommonName: "acme CA"},
+		SerialNumber:   big.NewInt(42),
+		DNSNames:       []string{"billing.example.org"},
+		EmailAddresses: []string{"billing@example.org"},
+		URIs:           []*url.URL{uri},
+	}
+
+	testCases := []struct {
+		name             string
+		tls              *tls.ConnectionState
+		expectedStatus   int
+		expectedIdentity *clientcert.Identity
+	}{
+		{
+			name:           "no TLS",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "no client certificate",
+			tls:            &tls.ConnectionState{},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "verified client certificate",
+			tls:            &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{certificate}}},
+			expectedStatus: http.StatusOK,
+			expectedIdentity: &clientcert.Identity{
+				CommonName:     "billing",
+				Organization:   []string{"acme"},
+				DNSNames:       []string{"billing.example.org"},
+				EmailAddresses: []string{"billing@example.org"},
+				URIs:           []string{"spiffe://example.org/billing"},
+				SerialNumber:   "42",
+				Issuer:         "CN=acme CA",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			log, hook := test.NewNullLogger()
+
+			var got *clientcert.Identity
+
+			handler := clientcert.New(log)(http.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) {
+				got = clientcert.GetIdentity(request.Context())
+			}))
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.TLS = testCase.tls
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, request)
+
+			if testCase.expectedStatus != w.Code {
+				t.Errorf("expected status %d but got %d", testCase.expectedStatus, w.Code)
+			}
+
+			if !reflect.DeepEqual(testCase.expectedIdentity, got) {
+				t.Errorf("expected identity %+v but got %+v", testCase.expectedIdentity, got)
+			}
+
+			if testCase.expectedStatus == http.StatusUnauthorized {
+				if expected := `{"error":"client certificate required"}`; expected != w.Body.String() {
+					t.Errorf("expected body '%s' but got '%s'", expected, w.Body.String())
+				}
+
+				if len(hook.AllEntries()) != 1 {
+					t.Errorf("expected 1 log entry but got %d", len(hook.AllEntries()))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package clientcert provides a middleware demanding a verified TLS client certificate and functions to read the
+// client identity from the context.
+package clientcert
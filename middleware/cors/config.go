@@ -1,10 +1,70 @@
 package cors
 
-import "github.com/rebel-l/go-utils/slice"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
 
-// Config provides a configuration for the CORS middleware.
+	"github.com/rebel-l/go-utils/slice"
+)
+
+// Config provides a configuration for the CORS middleware. A config decoded from JSON is validated while decoding,
+// use Validate() to check a config built in code.
 type Config struct {
+	// AccessControlAllowOrigins contains exact origins like https://example.com, OriginAny or patterns allowing all
+	// subdomains like https://*.example.com.
 	AccessControlAllowOrigins slice.StringSlice `json:"access_control_allow_origins,omitempty"`
+
+	// AccessControlAllowOriginRegexps contains regular expressions matching the whole origin.
+	AccessControlAllowOriginRegexps slice.StringSlice `json:"access_control_allow_origin_regexps,omitempty"`
+
 	AccessControlAllowHeaders slice.StringSlice `json:"access_contol_allow_headers,omitempty"`
 	AccessControlMaxAge       int               `json:"access_control_max_age,omitempty"`
+
+	// AllowCredentials lets browsers send cookies and authorization headers, not allowed together with OriginAny.
+	AllowCredentials bool `json:"allow_credentials,omitempty"`
+
+	// ExposeHeaders lists the response headers readable by scripts.
+	ExposeHeaders slice.StringSlice `json:"expose_headers,omitempty"`
+
+	// Strict rejects preflight requests for disallowed origins, methods or headers with 403 and passes OPTIONS
+	// requests which aren't preflight requests to the handler of the route.
+	Strict bool `json:"strict,omitempty"`
+
+	// AllowPrivateNetworkOrigins lists the origins, in the format of AccessControlAllowOrigins, permitted to access
+	// the service in a private network from a public website.
+	AllowPrivateNetworkOrigins slice.StringSlice `json:"allow_private_network_origins,omitempty"`
+}
+
+// ErrCredentialsWithAnyOrigin is returned by Validate() if credentials are allowed for any origin.
+var ErrCredentialsWithAnyOrigin = errors.New("credentials must not be allowed together with origin " + OriginAny)
+
+// Validate returns an error wrapping ErrInvalidOrigin if an allowed origin or private network origin is malformed or
+// a regular expression doesn't compile and ErrCredentialsWithAnyOrigin if credentials are allowed for any origin.
+func (c Config) Validate() error {
+	origins, err := newOriginMatcher(c.AccessControlAllowOrigins, c.AccessControlAllowOriginRegexps)
+	if err != nil {
+		return err
+	}
+
+	if _, err = newOriginMatcher(c.AllowPrivateNetworkOrigins, nil); err != nil {
+		return fmt.Errorf("private network: %w", err)
+	}
+
+	if c.AllowCredentials && origins.any {
+		return ErrCredentialsWithAnyOrigin
+	}
+
+	return nil
+}
+
+// UnmarshalJSON decodes the config and validates it.
+func (c *Config) UnmarshalJSON(data []byte) error {
+	type plain Config // without methods to avoid recursion
+
+	if err := json.Unmarshal(data, (*plain)(c)); err != nil {
+		return err
+	}
+
+	return c.Validate()
 }
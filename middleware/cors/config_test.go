@@ -0,0 +1,135 @@
+package cors_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/go-utils/slice"
+
+	"github.com/rebel-l/smis/middleware/cors"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  cors.Config
+		invalid bool
+	}{
+		{
+			name:   "empty",
+			config: cors.Config{},
+		},
+		{
+			name: "valid",
+			config: cors.Config{
+				AccessControlAllowOrigins: slice.StringSlice{
+					"*", "null", "https://example.com", "https://*.example.com:8443",
+				},
+				AccessControlAllowOriginRegexps: slice.StringSlice{`https://[a-z]+\.example\.com`},
+			},
+		},
+		{
+			name:    "path",
+			config:  cors.Config{AccessControlAllowOrigins: slice.StringSlice{"https://example.com/"}},
+			invalid: true,
+		},
+		{
+			name:    "no scheme",
+			config:  cors.Config{AccessControlAllowOrigins: slice.StringSlice{"example.com"}},
+			invalid: true,
+		},
+		{
+			name:    "wildcard in the middle",
+			config:  cors.Config{AccessControlAllowOrigins: slice.StringSlice{"https://api.*.example.com"}},
+			invalid: true,
+		},
+		{
+			name:    "wildcard without dot",
+			config:  cors.Config{AccessControlAllowOrigins: slice.StringSlice{"https://*example.com"}},
+			invalid: true,
+		},
+		{
+			name:    "wildcard only",
+			config:  cors.Config{AccessControlAllowOrigins: slice.StringSlice{"https://*"}},
+			invalid: true,
+		},
+		{
+			name:    "private network",
+			config:  cors.Config{AllowPrivateNetworkOrigins: slice.StringSlice{"https://*.*.example.com"}},
+			invalid: true,
+		},
+		{
+			name:    "regular expression",
+			config:  cors.Config{AccessControlAllowOriginRegexps: slice.StringSlice{"https://(example.com"}},
+			invalid: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.config.Validate()
+			if testCase.invalid && !errors.Is(err, cors.ErrInvalidOrigin) {
+				t.Errorf("expected error '%v' but got '%v'", cors.ErrInvalidOrigin, err)
+			}
+
+			if !testCase.invalid && err != nil {
+				t.Errorf("expected no error but got '%v'", err)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_Credentials(t *testing.T) {
+	config := cors.Config{AccessControlAllowOrigins: slice.StringSlice{"*"}, AllowCredentials: true}
+	if err := config.Validate(); !errors.Is(err, cors.ErrCredentialsWithAnyOrigin) {
+		t.Errorf("expected error '%v' but got '%v'", cors.ErrCredentialsWithAnyOrigin, err)
+	}
+
+	config.AccessControlAllowOrigins = slice.StringSlice{"https://*.example.com"}
+	if err := config.Validate(); err != nil {
+		t.Errorf("expected no error but got '%v'", err)
+	}
+}
+
+func TestConfig_UnmarshalJSON(t *testing.T) {
+	var config cors.Config
+
+	data := []byte(`{"access_control_allow_origins":["https://*.example.com"],"access_control_max_age":10}`)
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("expected no error but got '%v'", err)
+	}
+
+	if !config.AccessControlAllowOrigins.IsIn("https://*.example.com") || config.AccessControlMaxAge != 10 {
+		t.Errorf("expected config to be decoded but got %+v", config)
+	}
+
+	data = []byte(`{"access_control_allow_origins":["https://*.*.example.com"]}`)
+	if err := json.Unmarshal(data, &config); !errors.Is(err, cors.ErrInvalidOrigin) {
+		t.Errorf("expected error '%v' but got '%v'", cors.ErrInvalidOrigin, err)
+	}
+}
+
+func TestNewWithLog_InvalidConfig(t *testing.T) {
+	log, hook := test.NewNullLogger()
+
+	cors.NewWithLog(log, mux.NewRouter(), cors.Config{
+		AccessControlAllowOrigins: slice.StringSlice{"https://*.*.example.com"},
+	})
+
+	entry := hook.LastEntry()
+	if entry == nil || entry.Level != logrus.ErrorLevel {
+		t.Fatalf("expected invalid config to be logged as error but got %v", entry)
+	}
+
+	expected := `invalid CORS config, the invalid entries never match: invalid origin: "https://*.*.example.com", ` +
+		`a wildcard is only allowed as first label of the host, e.g. https://*.example.com`
+	if expected != entry.Message {
+		t.Errorf("expected message '%s' but got '%s'", expected, entry.Message)
+	}
+}
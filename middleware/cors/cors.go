@@ -5,9 +5,12 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/gorilla/mux"
+
 	"github.com/rebel-l/go-utils/slice"
+	"github.com/rebel-l/smis/middleware/requestid"
 
-	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
 )
 
 const (
@@ -23,24 +26,65 @@ const (
 	// HeaderACAH is the header key for Access-Control-Allow-Headers
 	HeaderACAH = "Access-Control-Allow-Headers"
 
+	// HeaderACAC is the header key for Access-Control-Allow-Credentials
+	HeaderACAC = "Access-Control-Allow-Credentials"
+
+	// HeaderACEH is the header key for Access-Control-Expose-Headers
+	HeaderACEH = "Access-Control-Expose-Headers"
+
 	// HeaderACMA is the header key for Access-Control-Max-Age
 	HeaderACMA = "Access-Control-Max-Age"
 
 	// HeaderACRM is the header key for Access-Control-Request-Method
 	HeaderACRM = "Access-Control-Request-Method"
 
+	// HeaderACRH is the header key for Access-Control-Request-Headers
+	HeaderACRH = "Access-Control-Request-Headers"
+
+	// HeaderACRPN is the header key for Access-Control-Request-Private-Network
+	HeaderACRPN = "Access-Control-Request-Private-Network"
+
+	// HeaderACAPN is the header key for Access-Control-Allow-Private-Network
+	HeaderACAPN = "Access-Control-Allow-Private-Network"
+
 	// HeaderOrigin is the header key for Origin
 	HeaderOrigin = "Origin"
+
+	// HeaderVary is the header key for Vary
+	HeaderVary = "Vary"
 )
 
 type cors struct {
-	Config Config
-	Router *mux.Router
+	Config          Config
+	Router          *mux.Router
+	Log             logrus.FieldLogger
+	origins         *originMatcher
+	privateNetworks *originMatcher
 }
 
-// New returns a middleware to handle CORS requests.
+// New returns a middleware to handle CORS requests. Invalid entries of the allowed origins never match and
+// credentials are never allowed together with OriginAny, see Config.Validate(). As the allowed origin is reflected,
+// all responses vary by origin. An invalid config and rejected preflight requests are logged to the standard logger.
 func New(router *mux.Router, config Config) mux.MiddlewareFunc {
-	middleware := &cors{Config: config, Router: router}
+	return NewWithLog(logrus.StandardLogger(), router, config)
+}
+
+// NewWithLog returns a middleware to handle CORS requests like New() logging to log.
+func NewWithLog(log logrus.FieldLogger, router *mux.Router, config Config) mux.MiddlewareFunc {
+	if err := config.Validate(); err != nil {
+		log.Errorf("invalid CORS config, the invalid entries never match: %s", err)
+	}
+
+	origins, _ := newOriginMatcher(config.AccessControlAllowOrigins, config.AccessControlAllowOriginRegexps)
+	privateNetworks, _ := newOriginMatcher(config.AllowPrivateNetworkOrigins, nil)
+	middleware := &cors{
+		Config:          config,
+		Router:          router,
+		Log:             log,
+		origins:         origins,
+		privateNetworks: privateNetworks,
+	}
+
 	return middleware.handler
 }
 
@@ -50,11 +94,34 @@ func (c *cors) handler(next http.Handler) http.Handler {
 		// an ACA* header for all method even if the content type is the standard form.
 		// All other known browsers do correctly the OPTIONS request.
 
+		writer.Header().Add(HeaderVary, HeaderOrigin)
+
+		preflight := IsPreflight(request)
+		if c.Config.Strict && preflight {
+			if reason := c.checkPreflight(request); reason != "" {
+				requestid.NewLoggerFromContext(request.Context(), c.Log).
+					Warnf("CORS preflight rejected: %s | %s", reason, request.RequestURI)
+				writer.WriteHeader(http.StatusForbidden)
+
+				return
+			}
+		}
+
 		origin := request.Header.Get(HeaderOrigin)
-		if c.Config.AccessControlAllowOrigins.IsIn(origin) || c.Config.AccessControlAllowOrigins.IsIn("*") {
+		if c.origins.allows(origin) {
 			// origin
 			writer.Header().Set(HeaderACAO, origin)
 
+			// credentials
+			if c.Config.AllowCredentials && !c.origins.any {
+				writer.Header().Set(HeaderACAC, "true")
+			}
+
+			// exposed headers
+			if len(c.Config.ExposeHeaders) > 0 {
+				writer.Header().Set(HeaderACEH, strings.Join(c.Config.ExposeHeaders, ","))
+			}
+
 			// methods
 			writer.Header().Set(HeaderACAM, c.getMethods(request))
 
@@ -63,9 +130,15 @@ func (c *cors) handler(next http.Handler) http.Handler {
 
 			// max age
 			writer.Header().Set(HeaderACMA, c.getMaxAge())
+
+			// private network access
+			if preflight && requestsPrivateNetwork(request) && c.privateNetworks.allows(origin) {
+				writer.Header().Set(HeaderACAPN, "true")
+			}
 		}
 
-		if request.Method == http.MethodOptions {
+		// without strict mode all OPTIONS requests are treated as preflight requests
+		if preflight || (!c.Config.Strict && request.Method == http.MethodOptions) {
 			writer.WriteHeader(http.StatusNoContent)
 		} else {
 			next.ServeHTTP(writer, request)
@@ -83,6 +156,18 @@ func (c *cors) getMaxAge() string {
 }
 
 func (c *cors) getMethods(request *http.Request) string {
+	methods, _ := c.matchRoute(request)
+
+	if methods == nil || methods.IsNotIn(http.MethodOptions) {
+		methods = append(methods, http.MethodOptions)
+	}
+
+	return strings.Join(methods, ",")
+}
+
+// matchRoute returns the methods of the route matching the request for the method requested by the preflight request
+// and whether a route matches at all.
+func (c *cors) matchRoute(request *http.Request) (slice.StringSlice, bool) {
 	var methods slice.StringSlice
 
 	reqMethod := request.Header.Get(HeaderACRM)
@@ -97,13 +182,62 @@ func (c *cors) getMethods(request *http.Request) string {
 	}
 
 	routerMatch := &mux.RouteMatch{}
-	if c.Router.Match(simReq, routerMatch) && routerMatch.MatchErr == nil {
-		methods, _ = routerMatch.Route.GetMethods()
+	if !c.Router.Match(simReq, routerMatch) || routerMatch.MatchErr != nil {
+		return nil, false
 	}
 
-	if methods == nil || methods.IsNotIn(http.MethodOptions) {
-		methods = append(methods, http.MethodOptions)
+	methods, _ = routerMatch.Route.GetMethods()
+
+	return methods, true
+}
+
+// checkPreflight returns the reason why the preflight request is rejected or an empty string if it is allowed.
+func (c *cors) checkPreflight(request *http.Request) string {
+	origin := request.Header.Get(HeaderOrigin)
+	if !c.origins.allows(origin) {
+		return fmt.Sprintf("origin %s not allowed", origin)
 	}
 
-	return strings.Join(methods, ",")
+	if _, ok := c.matchRoute(request); !ok {
+		return fmt.Sprintf("method %s not allowed", request.Header.Get(HeaderACRM))
+	}
+
+	if requestsPrivateNetwork(request) && !c.privateNetworks.allows(origin) {
+		return fmt.Sprintf("private network access not allowed for origin %s", origin)
+	}
+
+	if c.Config.AccessControlAllowHeaders.IsIn("*") {
+		return ""
+	}
+
+	for _, header := range strings.Split(request.Header.Get(HeaderACRH), ",") {
+		header = strings.TrimSpace(header)
+		if header != "" && !c.allowsHeader(header) {
+			return fmt.Sprintf("header %s not allowed", header)
+		}
+	}
+
+	return ""
+}
+
+func (c *cors) allowsHeader(header string) bool {
+	for _, allowed := range c.Config.AccessControlAllowHeaders {
+		if strings.EqualFold(allowed, header) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsPreflight returns true for OPTIONS requests sent by browsers before the actual cross origin request.
+func IsPreflight(request *http.Request) bool {
+	return request.Method == http.MethodOptions &&
+		request.Header.Get(HeaderOrigin) != "" &&
+		request.Header.Get(HeaderACRM) != ""
+}
+
+// requestsPrivateNetwork returns true if the browser asks for access to a private network from a public website.
+func requestsPrivateNetwork(request *http.Request) bool {
+	return strings.EqualFold(request.Header.Get(HeaderACRPN), "true")
 }
@@ -15,6 +15,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/rebel-l/smis/tests/mocks/http_mock"
+	"github.com/sirupsen/logrus/hooks/test"
 )
 
 func createHandler(ctrl *gomock.Controller) *http_mock.MockHandler {
@@ -156,6 +157,310 @@ func TestNew(t *testing.T) { // nolint: funlen
 			if testCase.expectedMaxAge != maxAge {
 				t.Errorf("expected max age to be '%s' but got '%s'", testCase.expectedMaxAge, maxAge)
 			}
+
+			vary := resp.Header.Get(cors.HeaderVary)
+			if cors.HeaderOrigin != vary {
+				t.Errorf("expected vary to be '%s' but got '%s'", cors.HeaderOrigin, vary)
+			}
+		})
+	}
+}
+
+func TestNew_Credentials(t *testing.T) { // nolint: funlen
+	router := mux.NewRouter()
+	router.HandleFunc("/", func(_ http.ResponseWriter, _ *http.Request) {}).Methods(http.MethodGet)
+
+	testCases := []struct {
+		name                string
+		method              string
+		config              cors.Config
+		expectedCredentials string
+		expectedExpose      string
+	}{
+		{
+			name:   "credentials and exposed headers",
+			method: http.MethodGet,
+			config: cors.Config{
+				AccessControlAllowOrigins: slice.StringSlice{"http://example.com"},
+				AllowCredentials:          true,
+				ExposeHeaders:             slice.StringSlice{"X-Request-ID", "Location"},
+			},
+			expectedCredentials: "true",
+			expectedExpose:      "X-Request-ID,Location",
+		},
+		{
+			name:   "preflight with credentials",
+			method: http.MethodOptions,
+			config: cors.Config{
+				AccessControlAllowOrigins: slice.StringSlice{"https://*.example.org", "http://example.com"},
+				AllowCredentials:          true,
+			},
+			expectedCredentials: "true",
+		},
+		{
+			name:   "no credentials",
+			method: http.MethodGet,
+			config: cors.Config{
+				AccessControlAllowOrigins: slice.StringSlice{"http://example.com"},
+			},
+		},
+		{
+			name:   "credentials refused for any origin",
+			method: http.MethodGet,
+			config: cors.Config{
+				AccessControlAllowOrigins: slice.StringSlice{"*"},
+				AllowCredentials:          true,
+			},
+		},
+		{
+			name:   "origin not allowed",
+			method: http.MethodGet,
+			config: cors.Config{
+				AccessControlAllowOrigins: slice.StringSlice{"http://example.org"},
+				AllowCredentials:          true,
+				ExposeHeaders:             slice.StringSlice{"Location"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := httptest.NewRequest(testCase.method, "/", nil)
+			request.Header.Set(cors.HeaderOrigin, "http://example.com")
+
+			handler := cors.New(router, testCase.config)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, request)
+			resp := w.Result()
+			if err := resp.Body.Close(); err != nil {
+				t.Fatalf("failed to close body: %s", err)
+			}
+
+			credentials := resp.Header.Get(cors.HeaderACAC)
+			if testCase.expectedCredentials != credentials {
+				t.Errorf("expected credentials to be '%s' but got '%s'", testCase.expectedCredentials, credentials)
+			}
+
+			expose := resp.Header.Get(cors.HeaderACEH)
+			if testCase.expectedExpose != expose {
+				t.Errorf("expected exposed headers to be '%s' but got '%s'", testCase.expectedExpose, expose)
+			}
+		})
+	}
+}
+
+func TestNew_Strict(t *testing.T) { // nolint: funlen
+	router := mux.NewRouter()
+	router.HandleFunc("/", func(_ http.ResponseWriter, _ *http.Request) {}).
+		Methods(http.MethodPost, http.MethodOptions)
+
+	config := cors.Config{
+		AccessControlAllowOrigins: slice.StringSlice{"http://example.com"},
+		AccessControlAllowHeaders: slice.StringSlice{"Content-Type", "X-Token"},
+		Strict:                    true,
+	}
+
+	testCases := []struct {
+		name           string
+		origin         string
+		method         string
+		headers        string
+		preflight      bool
+		expectedStatus int
+		expectedOrigin string
+		expectedLog    string
+	}{
+		{
+			name:           "allowed",
+			origin:         "http://example.com",
+			method:         http.MethodPost,
+			headers:        "content-type, x-token",
+			preflight:      true,
+			expectedStatus: http.StatusNoContent,
+			expectedOrigin: "http://example.com",
+		},
+		{
+			name:           "origin not allowed",
+			origin:         "http://example.org",
+			method:         http.MethodPost,
+			preflight:      true,
+			expectedStatus: http.StatusForbidden,
+			expectedLog:    "CORS preflight rejected: origin http://example.org not allowed | /",
+		},
+		{
+			name:           "method not allowed",
+			origin:         "http://example.com",
+			method:         http.MethodDelete,
+			preflight:      true,
+			expectedStatus: http.StatusForbidden,
+			expectedLog:    "CORS preflight rejected: method DELETE not allowed | /",
+		},
+		{
+			name:           "header not allowed",
+			origin:         "http://example.com",
+			method:         http.MethodPost,
+			headers:        "Content-Type,Authorization",
+			preflight:      true,
+			expectedStatus: http.StatusForbidden,
+			expectedLog:    "CORS preflight rejected: header Authorization not allowed | /",
+		},
+		{
+			name:           "options without origin is passed through",
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "options without requested method is passed through",
+			origin:         "http://example.com",
+			expectedStatus: http.StatusAccepted,
+			expectedOrigin: "http://example.com",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			log, hook := test.NewNullLogger()
+			next := http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusAccepted)
+			})
+			handler := cors.NewWithLog(log, router, config)(next)
+
+			request := httptest.NewRequest(http.MethodOptions, "/", nil)
+			if testCase.origin != "" {
+				request.Header.Set(cors.HeaderOrigin, testCase.origin)
+			}
+
+			if testCase.method != "" {
+				request.Header.Set(cors.HeaderACRM, testCase.method)
+			}
+
+			if testCase.headers != "" {
+				request.Header.Set(cors.HeaderACRH, testCase.headers)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, request)
+			resp := w.Result()
+			if err := resp.Body.Close(); err != nil {
+				t.Fatalf("failed to close body: %s", err)
+			}
+
+			if testCase.expectedStatus != resp.StatusCode {
+				t.Errorf("expected status %d but got %d", testCase.expectedStatus, resp.StatusCode)
+			}
+
+			origin := resp.Header.Get(cors.HeaderACAO)
+			if testCase.expectedOrigin != origin {
+				t.Errorf("expected origin to be '%s' but got '%s'", testCase.expectedOrigin, origin)
+			}
+
+			var message string
+			if entry := hook.LastEntry(); entry != nil {
+				message = entry.Message
+			}
+
+			if testCase.expectedLog != message {
+				t.Errorf("expected log message '%s' but got '%s'", testCase.expectedLog, message)
+			}
+		})
+	}
+}
+
+func TestNew_PrivateNetwork(t *testing.T) { // nolint: funlen
+	router := mux.NewRouter()
+	router.HandleFunc("/", func(_ http.ResponseWriter, _ *http.Request) {}).
+		Methods(http.MethodGet, http.MethodOptions)
+
+	config := cors.Config{
+		AccessControlAllowOrigins:  slice.StringSlice{"https://tools.example.com", "https://www.example.com"},
+		AllowPrivateNetworkOrigins: slice.StringSlice{"https://tools.example.com"},
+	}
+
+	testCases := []struct {
+		name           string
+		origin         string
+		method         string
+		privateNetwork string
+		strict         bool
+		expectedStatus int
+		expectedHeader string
+	}{
+		{
+			name:           "allowed",
+			origin:         "https://tools.example.com",
+			method:         http.MethodOptions,
+			privateNetwork: "true",
+			expectedStatus: http.StatusNoContent,
+			expectedHeader: "true",
+		},
+		{
+			name:           "not requested",
+			origin:         "https://tools.example.com",
+			method:         http.MethodOptions,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "origin not allowed for private network",
+			origin:         "https://www.example.com",
+			method:         http.MethodOptions,
+			privateNetwork: "true",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "origin not allowed for private network in strict mode",
+			origin:         "https://www.example.com",
+			method:         http.MethodOptions,
+			privateNetwork: "true",
+			strict:         true,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "allowed in strict mode",
+			origin:         "https://tools.example.com",
+			method:         http.MethodOptions,
+			privateNetwork: "true",
+			strict:         true,
+			expectedStatus: http.StatusNoContent,
+			expectedHeader: "true",
+		},
+		{
+			name:           "no preflight",
+			origin:         "https://tools.example.com",
+			method:         http.MethodGet,
+			privateNetwork: "true",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			config.Strict = testCase.strict
+			log, _ := test.NewNullLogger()
+			handler := cors.NewWithLog(log, router, config)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+
+			request := httptest.NewRequest(testCase.method, "/", nil)
+			request.Header.Set(cors.HeaderOrigin, testCase.origin)
+			request.Header.Set(cors.HeaderACRM, http.MethodGet)
+
+			if testCase.privateNetwork != "" {
+				request.Header.Set(cors.HeaderACRPN, testCase.privateNetwork)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, request)
+			resp := w.Result()
+			if err := resp.Body.Close(); err != nil {
+				t.Fatalf("failed to close body: %s", err)
+			}
+
+			if testCase.expectedStatus != resp.StatusCode {
+				t.Errorf("expected status %d but got %d", testCase.expectedStatus, resp.StatusCode)
+			}
+
+			header := resp.Header.Get(cors.HeaderACAPN)
+			if testCase.expectedHeader != header {
+				t.Errorf("expected private network header '%s' but got '%s'", testCase.expectedHeader, header)
+			}
 		})
 	}
 }
@@ -0,0 +1,166 @@
+package cors
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	// OriginAny allows all origins
+	OriginAny = "*"
+
+	// OriginNull is the origin sent by browsers for sandboxed documents or local files
+	OriginNull = "null"
+
+	wildcardPrefix = "*."
+)
+
+// ErrInvalidOrigin is wrapped by all errors about invalid entries in the allowed origins.
+var ErrInvalidOrigin = errors.New("invalid origin")
+
+// wildcardOrigin matches all subdomains of domain for the given scheme and port.
+type wildcardOrigin struct {
+	scheme string
+	domain string
+	port   string
+}
+
+// originMatcher decides whether an origin is allowed. Invalid entries of the config are skipped, so they never
+// match. The first error is returned.
+type originMatcher struct {
+	any       bool
+	exact     map[string]bool
+	wildcards []wildcardOrigin
+	regexps   []*regexp.Regexp
+}
+
+func newOriginMatcher(origins, regexps []string) (*originMatcher, error) {
+	var first error
+
+	m := &originMatcher{exact: make(map[string]bool)}
+
+	for _, origin := range origins {
+		if err := m.addOrigin(origin); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	for _, expr := range regexps {
+		// anchored, otherwise https://a\.example\.com would match https://a.example.com.evil.com
+		regex, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			if first == nil {
+				first = fmt.Errorf("%w: regular expression %q: %v", ErrInvalidOrigin, expr, err)
+			}
+
+			continue
+		}
+
+		m.regexps = append(m.regexps, regex)
+	}
+
+	return m, first
+}
+
+func (m *originMatcher) addOrigin(origin string) error {
+	if origin == OriginAny {
+		m.any = true
+		return nil
+	}
+
+	if origin == OriginNull {
+		m.exact[origin] = true
+		return nil
+	}
+
+	u, err := parseOrigin(origin)
+	if err != nil {
+		return err
+	}
+
+	host := u.Hostname()
+	if !strings.Contains(host, "*") {
+		m.exact[strings.ToLower(origin)] = true
+		return nil
+	}
+
+	domain := strings.TrimPrefix(host, wildcardPrefix)
+	if !strings.HasPrefix(host, wildcardPrefix) || domain == "" || strings.Contains(domain, "*") {
+		return fmt.Errorf("%w: %q, a wildcard is only allowed as first label of the host, e.g. https://*.example.com",
+			ErrInvalidOrigin, origin)
+	}
+
+	m.wildcards = append(m.wildcards, wildcardOrigin{
+		scheme: strings.ToLower(u.Scheme),
+		domain: strings.ToLower(domain),
+		port:   u.Port(),
+	})
+
+	return nil
+}
+
+// allows returns true if the origin sent by the client matches one of the allowed origins.
+func (m *originMatcher) allows(origin string) bool {
+	if origin == "" {
+		return false
+	}
+
+	if m.any || m.exact[strings.ToLower(origin)] {
+		return true
+	}
+
+	for _, regex := range m.regexps {
+		if regex.MatchString(origin) {
+			return true
+		}
+	}
+
+	if len(m.wildcards) == 0 {
+		return false
+	}
+
+	u, err := parseOrigin(origin)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+
+	for _, w := range m.wildcards {
+		if w.matches(strings.ToLower(u.Scheme), host, u.Port()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches requires at least one label in front of the domain, separated by a dot. So *.example.com matches
+// a.example.com and a.b.example.com but neither example.com nor evil-example.com.
+func (w wildcardOrigin) matches(scheme, host, port string) bool {
+	if scheme != w.scheme || port != w.port {
+		return false
+	}
+
+	sub := strings.TrimSuffix(host, "."+w.domain)
+
+	return sub != host && sub != "" && !strings.HasSuffix(sub, ".")
+}
+
+// parseOrigin parses an origin consisting of scheme, host and optional port as sent by browsers.
+func parseOrigin(origin string) (*url.URL, error) {
+	u, err := url.Parse(origin)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrInvalidOrigin, origin, err)
+	}
+
+	if u.Scheme == "" || u.Hostname() == "" || u.User != nil || u.Opaque != "" ||
+		u.Path != "" || u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("%w: %q, expected scheme://host[:port]", ErrInvalidOrigin, origin)
+	}
+
+	return u, nil
+}
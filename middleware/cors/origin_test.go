@@ -0,0 +1,74 @@
+package cors_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/go-utils/slice"
+	"github.com/rebel-l/smis/middleware/cors"
+)
+
+func TestNew_OriginMatching(t *testing.T) { // nolint: funlen
+	config := cors.Config{
+		AccessControlAllowOrigins:       slice.StringSlice{"https://*.example.com", "http://*.local.test:8080", "null"},
+		AccessControlAllowOriginRegexps: slice.StringSlice{`https://pr-\d+\.preview\.example\.org`},
+	}
+
+	testCases := []struct {
+		origin  string
+		allowed bool
+	}{
+		{origin: "https://a.example.com", allowed: true},
+		{origin: "https://pr-123.preview.example.com", allowed: true},
+		{origin: "https://A.Example.COM", allowed: true},
+		{origin: "https://example.com", allowed: false},
+		{origin: "https://evil-example.com", allowed: false},
+		{origin: "https://a.example.com.evil.com", allowed: false},
+		{origin: "https://evil.com?.example.com", allowed: false},
+		{origin: "https://evil.com/.example.com", allowed: false},
+		{origin: "https://evil.com#.example.com", allowed: false},
+		{origin: "https://user@a.example.com", allowed: false},
+		{origin: "http://a.example.com", allowed: false},
+		{origin: "https://a.example.com:8443", allowed: false},
+		{origin: "https://.example.com", allowed: false},
+		{origin: "http://app.local.test:8080", allowed: true},
+		{origin: "http://app.local.test", allowed: false},
+		{origin: "https://pr-42.preview.example.org", allowed: true},
+		{origin: "https://pr-42.preview.example.org.evil.com", allowed: false},
+		{origin: "https://pr-x.preview.example.org", allowed: false},
+		{origin: "null", allowed: true},
+		{origin: "", allowed: false},
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/", func(_ http.ResponseWriter, _ *http.Request) {}).Methods(http.MethodGet)
+
+	handler := cors.New(router, config)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+
+	for _, testCase := range testCases {
+		t.Run(testCase.origin, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.Header.Set(cors.HeaderOrigin, testCase.origin)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, request)
+
+			resp := w.Result()
+			if err := resp.Body.Close(); err != nil {
+				t.Fatalf("failed to close body: %s", err)
+			}
+
+			origin := resp.Header.Get(cors.HeaderACAO)
+			if testCase.allowed && origin != testCase.origin {
+				t.Errorf("expected origin '%s' to be allowed but got '%s'", testCase.origin, origin)
+			}
+
+			if !testCase.allowed && origin != "" {
+				t.Errorf("expected origin '%s' to be forbidden but got '%s'", testCase.origin, origin)
+			}
+		})
+	}
+}
@@ -0,0 +1,2 @@
+// Package recovery provides a middleware which recovers from panics in handlers and responds with an error.
+package recovery
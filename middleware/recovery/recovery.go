@@ -0,0 +1,83 @@
+package recovery
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis/middleware/requestid"
+	"github.com/rebel-l/smis/middleware/writer"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ErrorMessage is the message sent to the client if a panic was recovered
+	ErrorMessage = "internal server error"
+
+	// LogFieldStack is the key of the log field containing the stack trace
+	LogFieldStack = "stack"
+)
+
+type recovery struct {
+	Log logrus.FieldLogger
+}
+
+type errorJSON struct {
+	Error string `json:"error"`
+}
+
+// New returns a middleware recovering from panics. The panic is logged with stack trace and the client receives a
+// 500 JSON error. If the response was sent partially already, the connection is aborted by panicking with
+// http.ErrAbortHandler instead. It should be the outermost middleware of a chain.
+func New(log logrus.FieldLogger) mux.MiddlewareFunc {
+	mw := &recovery{Log: log}
+	return mw.handler
+}
+
+func (r *recovery) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		recorder := writer.NewRecorder(w)
+
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+
+			// http.ErrAbortHandler is used to abort a response on purpose and is handled by the server
+			if rec == http.ErrAbortHandler { // nolint: goerr113, errorlint
+				panic(rec)
+			}
+
+			r.newLogger(recorder, request).
+				WithField(LogFieldStack, string(debug.Stack())).
+				Errorf("panic recovered: %v", rec)
+
+			// appending the error to a response sent partially would corrupt it, so the connection is aborted
+			if recorder.WroteHeader() {
+				panic(http.ErrAbortHandler)
+			}
+
+			recorder.Header().Set("Content-Type", "application/json")
+			recorder.WriteHeader(http.StatusInternalServerError)
+
+			response, _ := json.Marshal(errorJSON{Error: ErrorMessage})
+			_, _ = recorder.Write(response)
+		}()
+
+		next.ServeHTTP(recorder, request)
+	})
+}
+
+// newLogger returns a logger with the request ID. As this middleware runs before the requestid middleware, the ID
+// is taken from the response header if the context doesn't contain it.
+func (r *recovery) newLogger(writer http.ResponseWriter, request *http.Request) logrus.FieldLogger {
+	if requestid.GetID(request.Context()) != "" {
+		return requestid.NewLoggerFromContext(request.Context(), r.Log)
+	}
+
+	return requestid.NewLogger(writer.Header().Get(requestid.HeaderRID), r.Log)
+}
@@ -0,0 +1,154 @@
+package recovery_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/rebel-l/smis/middleware/recovery"
+	"github.com/rebel-l/smis/middleware/requestid"
+	"github.com/rebel-l/smis/tests/mocks/http_mock"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestNew(t *testing.T) { // nolint: funlen
+	testCases := []struct {
+		name         string
+		panicValue   interface{}
+		requestID    string
+		expectedCode int
+		expectedBody string
+		expectedLogs int
+	}{
+		{
+			name:         "no panic",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "panic",
+			panicValue:   "something went wrong",
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"error":"internal server error"}`,
+			expectedLogs: 1,
+		},
+		{
+			name:         "panic with request ID",
+			panicValue:   "something went wrong",
+			requestID:    "abc-1",
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"error":"internal server error"}`,
+			expectedLogs: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			handler := http_mock.NewMockHandler(ctrl)
+			handler.EXPECT().ServeHTTP(gomock.Any(), gomock.Any()).Times(1).Do(
+				func(_ http.ResponseWriter, _ *http.Request) {
+					if testCase.panicValue != nil {
+						panic(testCase.panicValue)
+					}
+				})
+
+			log, hook := test.NewNullLogger()
+
+			var next http.Handler = handler
+			if testCase.requestID != "" {
+				// the requestid middleware runs inside of the recovery middleware
+				idLog, _ := test.NewNullLogger()
+				config := requestid.Config{Generator: requestid.NewCounterGenerator("abc-")}
+				next = requestid.NewWithConfig(idLog, config)(handler)
+			}
+
+			w := httptest.NewRecorder()
+			recovery.New(log).Middleware(next).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if testCase.expectedCode != w.Code {
+				t.Errorf("expected code %d but got %d", testCase.expectedCode, w.Code)
+			}
+
+			if testCase.expectedBody != w.Body.String() {
+				t.Errorf("expected body '%s' but got '%s'", testCase.expectedBody, w.Body.String())
+			}
+
+			if testCase.expectedLogs != len(hook.AllEntries()) {
+				t.Fatalf("expected %d log entries but got %d", testCase.expectedLogs, len(hook.AllEntries()))
+			}
+
+			if testCase.expectedLogs == 0 {
+				return
+			}
+
+			entry := hook.LastEntry()
+			if entry.Level != logrus.ErrorLevel {
+				t.Errorf("expected log level '%s' but got '%s'", logrus.ErrorLevel, entry.Level)
+			}
+
+			if entry.Data[string(requestid.ContextKeyRequestID)] != testCase.requestID {
+				t.Errorf(
+					"expected request ID '%s' but got '%v'",
+					testCase.requestID,
+					entry.Data[string(requestid.ContextKeyRequestID)],
+				)
+			}
+
+			if _, ok := entry.Data[recovery.LogFieldStack]; !ok {
+				t.Error("expected log entry to contain the stack trace")
+			}
+		})
+	}
+}
+
+func TestNew_AbortHandler(t *testing.T) {
+	defer func() {
+		if rec := recover(); rec != http.ErrAbortHandler {
+			t.Errorf("expected panic '%v' to be passed through but got '%v'", http.ErrAbortHandler, rec)
+		}
+	}()
+
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+
+	recovery.New(logrus.New()).
+		Middleware(handler).
+		ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestNew_PartialWrite(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	w := httptest.NewRecorder()
+
+	defer func() {
+		if rec := recover(); rec != http.ErrAbortHandler {
+			t.Errorf("expected panic '%v' to abort the response but got '%v'", http.ErrAbortHandler, rec)
+		}
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected code %d but got %d", http.StatusOK, w.Code)
+		}
+
+		if w.Body.String() != `{"partial":` {
+			t.Errorf("expected body '%s' but got '%s'", `{"partial":`, w.Body.String())
+		}
+
+		if len(hook.AllEntries()) != 1 {
+			t.Errorf("expected 1 log entry but got %d", len(hook.AllEntries()))
+		}
+	}()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"partial":`))
+		panic("something went wrong")
+	})
+
+	recovery.New(log).Middleware(handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+}
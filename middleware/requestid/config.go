@@ -0,0 +1,53 @@
+package requestid
+
+import "strings"
+
+const (
+	// MaxLengthDefault is the default maximum length of an incoming RequestID
+	MaxLengthDefault = 128
+
+	// CharsetDefault contains the characters an incoming RequestID may consist of by default
+	CharsetDefault = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_.:"
+)
+
+// Config provides a configuration for the requestid middleware. If TrustIncoming is true, the RequestID sent in the
+// header by the client is used as long as it is not longer than MaxLength and consists only of characters contained
+// in Charset. Otherwise a new RequestID is created by the Generator, which defaults to UUID v4.
+type Config struct {
+	TrustIncoming bool      `json:"trust_incoming,omitempty"`
+	MaxLength     int       `json:"max_length,omitempty"`
+	Charset       string    `json:"charset,omitempty"`
+	Generator     Generator `json:"-"`
+}
+
+func (c Config) isValid(requestID string) bool {
+	maxLength := c.MaxLength
+	if maxLength <= 0 {
+		maxLength = MaxLengthDefault
+	}
+
+	if requestID == "" || len(requestID) > maxLength {
+		return false
+	}
+
+	charset := c.Charset
+	if charset == "" {
+		charset = CharsetDefault
+	}
+
+	for _, r := range requestID {
+		if !strings.ContainsRune(charset, r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c Config) getGenerator() Generator {
+	if c.Generator == nil {
+		return NewUUIDGenerator()
+	}
+
+	return c.Generator
+}
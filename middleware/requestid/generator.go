@@ -0,0 +1,89 @@
+package requestid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// Generator creates new RequestIDs.
+type Generator interface {
+	Generate() string
+}
+
+// GeneratorFunc is an adapter to use ordinary functions as Generator.
+type GeneratorFunc func() string
+
+// Generate calls f().
+func (f GeneratorFunc) Generate() string {
+	return f()
+}
+
+// NewUUIDGenerator returns a generator creating random UUIDs (version 4).
+func NewUUIDGenerator() Generator {
+	return GeneratorFunc(func() string {
+		return uuid.New().String()
+	})
+}
+
+// NewULIDGenerator returns a generator creating ULIDs, which are lexicographically sortable by creation time.
+func NewULIDGenerator() Generator {
+	return GeneratorFunc(func() string {
+		var id [16]byte
+
+		ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+
+		var timestamp [8]byte
+
+		binary.BigEndian.PutUint64(timestamp[:], ms)
+		copy(id[:6], timestamp[2:])
+
+		if _, err := rand.Read(id[6:]); err != nil {
+			// entropy is exhausted, fall back to the time as the best effort
+			binary.BigEndian.PutUint64(id[8:], uint64(time.Now().UnixNano()))
+		}
+
+		return encodeCrockford(id)
+	})
+}
+
+// NewCounterGenerator returns a generator creating monotonic increasing IDs with the given prefix, e.g. "test-1",
+// "test-2". It is meant for tests where RequestIDs need to be predictable.
+func NewCounterGenerator(prefix string) Generator {
+	var counter uint64
+
+	return GeneratorFunc(func() string {
+		return fmt.Sprintf("%s%d", prefix, atomic.AddUint64(&counter, 1))
+	})
+}
+
+// encodeCrockford encodes the 128 bits into 26 characters of Crockford's base32. The bits are padded with two
+// leading zero bits to fit exactly.
+func encodeCrockford(id [16]byte) string {
+	out := make([]byte, 26)
+	pos := 0
+
+	var (
+		buffer uint32
+		bits   uint = 2
+	)
+
+	for _, b := range id {
+		buffer = buffer<<8 | uint32(b)
+		bits += 8
+
+		for bits >= 5 {
+			bits -= 5
+			out[pos] = crockfordAlphabet[(buffer>>bits)&31]
+			pos++
+		}
+	}
+
+	return string(out)
+}
@@ -0,0 +1,46 @@
+package requestid_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/rebel-l/smis/middleware/requestid"
+)
+
+func TestNewUUIDGenerator(t *testing.T) {
+	id := requestid.NewUUIDGenerator().Generate()
+	if _, err := uuid.Parse(id); err != nil {
+		t.Errorf("expected a valid UUID but got '%s': %s", id, err)
+	}
+}
+
+func TestNewULIDGenerator(t *testing.T) {
+	format := regexp.MustCompile("^[0-7][0-9A-HJKMNP-TV-Z]{25}$")
+	generator := requestid.NewULIDGenerator()
+
+	first := generator.Generate()
+	if !format.MatchString(first) {
+		t.Errorf("expected a valid ULID but got '%s'", first)
+	}
+
+	second := generator.Generate()
+	if first == second {
+		t.Errorf("expected ULIDs to be unique but got '%s' twice", first)
+	}
+
+	if first[:10] > second[:10] {
+		t.Errorf("expected time part of '%s' not to be greater than of '%s'", first, second)
+	}
+}
+
+func TestNewCounterGenerator(t *testing.T) {
+	generator := requestid.NewCounterGenerator("test-")
+
+	for _, expected := range []string{"test-1", "test-2", "test-3"} {
+		if got := generator.Generate(); expected != got {
+			t.Errorf("expected '%s' but got '%s'", expected, got)
+		}
+	}
+}
@@ -6,8 +6,6 @@ import (
 
 	"github.com/sirupsen/logrus"
 
-	"github.com/google/uuid"
-
 	"github.com/gorilla/mux"
 )
 
@@ -22,12 +20,18 @@ const (
 )
 
 type requestID struct {
-	Log logrus.FieldLogger
+	Config Config
+	Log    logrus.FieldLogger
 }
 
 // New returns the middleware handler generating the RequestID.
 func New(log logrus.FieldLogger) mux.MiddlewareFunc {
-	mw := &requestID{Log: log}
+	return NewWithConfig(log, Config{})
+}
+
+// NewWithConfig returns the middleware handler generating the RequestID or using the incoming one as configured.
+func NewWithConfig(log logrus.FieldLogger, config Config) mux.MiddlewareFunc {
+	mw := &requestID{Config: config, Log: log}
 	return mw.handler
 }
 
@@ -44,20 +48,32 @@ func GetID(ctx context.Context) string {
 func (r *requestID) handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 		// add request ID
-		ctx := attachRequestID(request.Context())
+		ctx := attachRequestID(request.Context(), r.getID(request))
 		request = request.WithContext(ctx)
 		log := NewLoggerFromContext(ctx, r.Log)
 		log.Info("Request start")
 
+		// add request ID to header, it must be done before the handler writes the response
+		writer.Header().Set(HeaderRID, GetID(ctx))
+
 		// handle next
 		next.ServeHTTP(writer, request)
 
-		// add request ID to header
-		writer.Header().Set(HeaderRID, GetID(ctx))
 		log.Info("Request finished")
 	})
 }
 
-func attachRequestID(ctx context.Context) context.Context {
-	return context.WithValue(ctx, ContextKeyRequestID, uuid.New().String())
+func (r *requestID) getID(request *http.Request) string {
+	if r.Config.TrustIncoming {
+		incoming := request.Header.Get(HeaderRID)
+		if r.Config.isValid(incoming) {
+			return incoming
+		}
+	}
+
+	return r.Config.getGenerator().Generate()
+}
+
+func attachRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, ContextKeyRequestID, requestID)
 }
@@ -10,11 +10,13 @@ import (
 
 	"github.com/golang/mock/gomock"
 
+	"github.com/rebel-l/smis"
 	"github.com/rebel-l/smis/middleware/requestid"
 	"github.com/rebel-l/smis/tests/mocks/http_mock"
 	"github.com/rebel-l/smis/tests/mocks/logrus_mock"
 
 	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
 )
 
 func createHandler(ctrl *gomock.Controller) *http_mock.MockHandler {
@@ -65,3 +67,142 @@ func TestGetID(t *testing.T) {
 		t.Errorf("context which didn't pass the middleware should not have a RequestID but got: %s", res)
 	}
 }
+
+func TestNewWithConfig(t *testing.T) { // nolint: funlen
+	testCases := []struct {
+		name       string
+		config     requestid.Config
+		incoming   string
+		expectedID string
+	}{
+		{
+			name:       "incoming not trusted",
+			config:     requestid.Config{Generator: requestid.NewCounterGenerator("gen-")},
+			incoming:   "upstream-id",
+			expectedID: "gen-1",
+		},
+		{
+			name: "incoming trusted",
+			config: requestid.Config{
+				TrustIncoming: true,
+				Generator:     requestid.NewCounterGenerator("gen-"),
+			},
+			incoming:   "upstream-id",
+			expectedID: "upstream-id",
+		},
+		{
+			name: "incoming missing",
+			config: requestid.Config{
+				TrustIncoming: true,
+				Generator:     requestid.NewCounterGenerator("gen-"),
+			},
+			expectedID: "gen-1",
+		},
+		{
+			name: "incoming too long",
+			config: requestid.Config{
+				TrustIncoming: true,
+				MaxLength:     5,
+				Generator:     requestid.NewCounterGenerator("gen-"),
+			},
+			incoming:   "upstream-id",
+			expectedID: "gen-1",
+		},
+		{
+			name: "incoming with invalid characters",
+			config: requestid.Config{
+				TrustIncoming: true,
+				Generator:     requestid.NewCounterGenerator("gen-"),
+			},
+			incoming:   "upstream id\n",
+			expectedID: "gen-1",
+		},
+		{
+			name: "incoming with custom charset",
+			config: requestid.Config{
+				TrustIncoming: true,
+				Charset:       "0123456789",
+				Generator:     requestid.NewCounterGenerator("gen-"),
+			},
+			incoming:   "12345",
+			expectedID: "12345",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			log, _ := test.NewNullLogger()
+
+			var gotID string
+
+			next := http.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) {
+				gotID = requestid.GetID(request.Context())
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			if testCase.incoming != "" {
+				request.Header.Set(requestid.HeaderRID, testCase.incoming)
+			}
+
+			w := httptest.NewRecorder()
+			requestid.NewWithConfig(log, testCase.config).Middleware(next).ServeHTTP(w, request)
+
+			if testCase.expectedID != gotID {
+				t.Errorf("expected request ID '%s' in context but got '%s'", testCase.expectedID, gotID)
+			}
+
+			if header := w.Header().Get(requestid.HeaderRID); testCase.expectedID != header {
+				t.Errorf("expected request ID '%s' in header but got '%s'", testCase.expectedID, header)
+			}
+		})
+	}
+}
+
+func TestNew_HeaderWithWrittenResponse(t *testing.T) {
+	log, _ := test.NewNullLogger()
+	response := &smis.Response{Log: log}
+
+	testCases := []struct {
+		name string
+		next http.HandlerFunc
+	}{
+		{
+			name: "WriteHeader",
+			next: func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusAccepted)
+			},
+		},
+		{
+			name: "WriteJSON",
+			next: func(writer http.ResponseWriter, _ *http.Request) {
+				response.WriteJSON(writer, http.StatusCreated, struct {
+					Name string `json:"name"`
+				}{Name: "test"})
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var expectedID string
+
+			next := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				expectedID = requestid.GetID(request.Context())
+				testCase.next(writer, request)
+			})
+
+			w := httptest.NewRecorder()
+			requestid.New(log).Middleware(next).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			resp := w.Result()
+
+			if err := resp.Body.Close(); err != nil {
+				t.Fatalf("failed to close body: %s", err)
+			}
+
+			header := resp.Header.Get(requestid.HeaderRID)
+			if expectedID == "" || expectedID != header {
+				t.Errorf("expected header %s to be '%s' but got '%s'", requestid.HeaderRID, expectedID, header)
+			}
+		})
+	}
+}
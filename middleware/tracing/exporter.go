@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// Exporter sends finished spans to a backend.
+type Exporter interface {
+	Export(span Span) error
+}
+
+// InMemoryExporter keeps all exported spans in memory. It is meant for tests.
+type InMemoryExporter struct {
+	mutex sync.Mutex
+	spans []Span
+}
+
+// NewInMemoryExporter returns an empty InMemoryExporter.
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+// Export stores the span.
+func (e *InMemoryExporter) Export(span Span) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.spans = append(e.spans, span)
+
+	return nil
+}
+
+// Spans returns a copy of all exported spans.
+func (e *InMemoryExporter) Spans() []Span {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	spans := make([]Span, len(e.spans))
+	copy(spans, e.spans)
+
+	return spans
+}
+
+// Reset removes all exported spans.
+func (e *InMemoryExporter) Reset() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.spans = nil
+}
+
+// JSONLinesExporter writes every span as JSON object in a separate line to a file.
+type JSONLinesExporter struct {
+	mutex   sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// NewJSONLinesExporter returns an exporter appending spans to the file. The file is created if it doesn't exist.
+func NewJSONLinesExporter(path string) (*JSONLinesExporter, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JSONLinesExporter{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+// Export writes the span as JSON line to the file.
+func (e *JSONLinesExporter) Export(span Span) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.encoder.Encode(span)
+}
+
+// Close closes the file.
+func (e *JSONLinesExporter) Close() error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.file.Close()
+}
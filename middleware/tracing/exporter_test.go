@@ -0,0 +1,86 @@
+package tracing_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rebel-l/smis/middleware/tracing"
+)
+
+func TestInMemoryExporter(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+
+	for _, name := range []string{"first", "second"} {
+		if err := exporter.Export(tracing.Span{Name: name}); err != nil {
+			t.Fatalf("failed to export span: %s", err)
+		}
+	}
+
+	spans := exporter.Spans()
+	if len(spans) != 2 || spans[0].Name != "first" || spans[1].Name != "second" {
+		t.Errorf("expected spans 'first' and 'second' but got %v", spans)
+	}
+
+	exporter.Reset()
+
+	if len(exporter.Spans()) != 0 {
+		t.Errorf("expected no spans after reset but got %d", len(exporter.Spans()))
+	}
+}
+
+func TestJSONLinesExporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tracing")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	path := filepath.Join(dir, "spans.jsonl")
+
+	exporter, err := tracing.NewJSONLinesExporter(path)
+	if err != nil {
+		t.Fatalf("failed to create exporter: %s", err)
+	}
+
+	for _, name := range []string{"first", "second"} {
+		if err = exporter.Export(tracing.Span{Name: name, TraceID: "abc"}); err != nil {
+			t.Fatalf("failed to export span: %s", err)
+		}
+	}
+
+	if err = exporter.Close(); err != nil {
+		t.Fatalf("failed to close exporter: %s", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open file: %s", err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var names []string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var span tracing.Span
+		if err = json.Unmarshal(scanner.Bytes(), &span); err != nil {
+			t.Fatalf("failed to decode line '%s': %s", scanner.Text(), err)
+		}
+
+		names = append(names, span.Name)
+	}
+
+	if len(names) != 2 || names[0] != "first" || names[1] != "second" {
+		t.Errorf("expected spans 'first' and 'second' in file but got %v", names)
+	}
+}
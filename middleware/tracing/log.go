@@ -0,0 +1,34 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// LogFieldTraceID is the key of the log field containing the trace ID
+	LogFieldTraceID = "traceID"
+
+	// LogFieldSpanID is the key of the log field containing the span ID
+	LogFieldSpanID = "spanID"
+)
+
+// NewLoggerFromContext returns a new FieldLogger containing trace and span ID of the span stored in the context. If
+// the context contains no span, the ancestor is returned unchanged.
+func NewLoggerFromContext(ctx context.Context, ancestorLog logrus.FieldLogger) logrus.FieldLogger {
+	var logger logrus.FieldLogger = logrus.StandardLogger()
+	if ancestorLog != nil {
+		logger = ancestorLog
+	}
+
+	span := GetSpan(ctx)
+	if span == nil {
+		return logger
+	}
+
+	return logger.WithFields(logrus.Fields{
+		LogFieldTraceID: span.TraceID,
+		LogFieldSpanID:  span.SpanID,
+	})
+}
@@ -0,0 +1,3 @@
+// Package tracing provides a middleware which propagates the W3C Trace Context (traceparent / tracestate headers)
+// and records a span per request. Finished spans are handed to an Exporter.
+package tracing
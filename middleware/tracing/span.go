@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"context"
+	"time"
+)
+
+type contextKey string
+
+const (
+	// ContextKeySpan is the key in the context where to find the span of the request
+	ContextKeySpan contextKey = "span"
+
+	// StatusUnset is the status of a span which is not finished yet
+	StatusUnset Status = "unset"
+
+	// StatusOK is the status of a span which finished successfully
+	StatusOK Status = "ok"
+
+	// StatusError is the status of a span which finished with an error, e.g. a server error
+	StatusError Status = "error"
+)
+
+// Status represents the outcome of a span.
+type Status string
+
+// Span represents a single unit of work within a trace, e.g. the handling of a request.
+type Span struct {
+	TraceID      string            `json:"trace_id"`
+	SpanID       string            `json:"span_id"`
+	ParentSpanID string            `json:"parent_span_id,omitempty"`
+	TraceState   string            `json:"trace_state,omitempty"`
+	Flags        byte              `json:"flags"`
+	Name         string            `json:"name"`
+	Start        time.Time         `json:"start"`
+	End          time.Time         `json:"end"`
+	Status       Status            `json:"status"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+}
+
+// TraceParent returns the traceparent to propagate this span.
+func (s *Span) TraceParent() TraceParent {
+	return TraceParent{TraceID: s.TraceID, SpanID: s.SpanID, Flags: s.Flags}
+}
+
+// Sampled returns true if the span should be exported.
+func (s *Span) Sampled() bool {
+	return s.TraceParent().Sampled()
+}
+
+// Duration returns the time between start and end of the span.
+func (s *Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// SetAttribute adds an attribute to the span.
+func (s *Span) SetAttribute(key, value string) {
+	if s.Attributes == nil {
+		s.Attributes = make(map[string]string)
+	}
+
+	s.Attributes[key] = value
+}
+
+// GetSpan returns the span stored in the context. Is nil if the context doesn't contain any span.
+func GetSpan(ctx context.Context) *Span {
+	if span, ok := ctx.Value(ContextKeySpan).(*Span); ok {
+		return span
+	}
+
+	return nil
+}
+
+// GetTraceID returns the trace ID of the span stored in the context. Is empty if the context doesn't contain any span.
+func GetTraceID(ctx context.Context) string {
+	if span := GetSpan(ctx); span != nil {
+		return span.TraceID
+	}
+
+	return ""
+}
+
+// GetSpanID returns the ID of the span stored in the context. Is empty if the context doesn't contain any span.
+func GetSpanID(ctx context.Context) string {
+	if span := GetSpan(ctx); span != nil {
+		return span.SpanID
+	}
+
+	return ""
+}
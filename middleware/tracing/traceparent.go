@@ -0,0 +1,119 @@
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// HeaderTraceParent is the header key for the W3C traceparent
+	HeaderTraceParent = "traceparent"
+
+	// HeaderTraceState is the header key for the W3C tracestate
+	HeaderTraceState = "tracestate"
+
+	// FlagSampled is the trace flag signaling that the trace is recorded
+	FlagSampled byte = 0x01
+
+	traceParentVersion = "00"
+	traceIDLength      = 16
+	spanIDLength       = 8
+)
+
+// TraceParent represents the parsed traceparent header.
+type TraceParent struct {
+	TraceID string
+	SpanID  string
+	Flags   byte
+}
+
+// Sampled returns true if the sampled flag is set.
+func (t TraceParent) Sampled() bool {
+	return t.Flags&FlagSampled == FlagSampled
+}
+
+// String returns the header value of the traceparent.
+func (t TraceParent) String() string {
+	return fmt.Sprintf("%s-%s-%s-%02x", traceParentVersion, t.TraceID, t.SpanID, t.Flags)
+}
+
+// ParseTraceParent parses the value of a traceparent header. Versions higher than 00 are parsed as far as they are
+// compatible to 00, as the specification demands.
+func ParseTraceParent(value string) (TraceParent, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return TraceParent{}, fmt.Errorf("traceparent %q has not enough fields", value)
+	}
+
+	version := parts[0]
+	if len(version) != 2 || !isLowerHex(version) || version == "ff" {
+		return TraceParent{}, fmt.Errorf("traceparent %q has invalid version", value)
+	}
+
+	if version == traceParentVersion && len(parts) != 4 {
+		return TraceParent{}, fmt.Errorf("traceparent %q has too many fields", value)
+	}
+
+	traceID, spanID, flags := parts[1], parts[2], parts[3]
+
+	if !isValidID(traceID, traceIDLength) {
+		return TraceParent{}, fmt.Errorf("traceparent %q has invalid trace id", value)
+	}
+
+	if !isValidID(spanID, spanIDLength) {
+		return TraceParent{}, fmt.Errorf("traceparent %q has invalid parent id", value)
+	}
+
+	if len(flags) != 2 || !isLowerHex(flags) {
+		return TraceParent{}, fmt.Errorf("traceparent %q has invalid flags", value)
+	}
+
+	f, _ := hex.DecodeString(flags)
+
+	return TraceParent{TraceID: traceID, SpanID: spanID, Flags: f[0]}, nil
+}
+
+// Inject sets the traceparent and tracestate of the span to the header, e.g. of an outgoing request. Nothing is set
+// if the span is nil.
+func Inject(header http.Header, span *Span) {
+	if span == nil {
+		return
+	}
+
+	header.Set(HeaderTraceParent, span.TraceParent().String())
+
+	if span.TraceState != "" {
+		header.Set(HeaderTraceState, span.TraceState)
+	}
+}
+
+func isValidID(id string, length int) bool {
+	return len(id) == length*2 && isLowerHex(id) && strings.Trim(id, "0") != ""
+}
+
+func isLowerHex(value string) bool {
+	for _, r := range value {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newID(length int) string {
+	id := make([]byte, length)
+
+	for {
+		if _, err := rand.Read(id); err != nil {
+			panic(fmt.Sprintf("failed to generate random id: %s", err))
+		}
+
+		if encoded := hex.EncodeToString(id); strings.Trim(encoded, "0") != "" {
+			return encoded
+		}
+	}
+}
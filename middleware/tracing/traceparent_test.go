@@ -0,0 +1,116 @@
+package tracing_test
+
+import (
+	"testing"
+
+	"github.com/rebel-l/smis/middleware/tracing"
+)
+
+func TestParseTraceParent(t *testing.T) { // nolint: funlen
+	testCases := []struct {
+		name          string
+		value         string
+		expected      tracing.TraceParent
+		expectedError bool
+	}{
+		{
+			name:  "valid sampled",
+			value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expected: tracing.TraceParent{
+				TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+				SpanID:  "00f067aa0ba902b7",
+				Flags:   tracing.FlagSampled,
+			},
+		},
+		{
+			name:  "valid not sampled",
+			value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+			expected: tracing.TraceParent{
+				TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+				SpanID:  "00f067aa0ba902b7",
+			},
+		},
+		{
+			name:  "future version with additional field",
+			value: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future",
+			expected: tracing.TraceParent{
+				TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+				SpanID:  "00f067aa0ba902b7",
+				Flags:   tracing.FlagSampled,
+			},
+		},
+		{
+			name:          "empty",
+			expectedError: true,
+		},
+		{
+			name:          "invalid version",
+			value:         "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedError: true,
+		},
+		{
+			name:          "version 00 with additional field",
+			value:         "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future",
+			expectedError: true,
+		},
+		{
+			name:          "zero trace id",
+			value:         "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			expectedError: true,
+		},
+		{
+			name:          "zero parent id",
+			value:         "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+			expectedError: true,
+		},
+		{
+			name:          "upper case",
+			value:         "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+			expectedError: true,
+		},
+		{
+			name:          "short trace id",
+			value:         "00-4bf92f3577b34da6-00f067aa0ba902b7-01",
+			expectedError: true,
+		},
+		{
+			name:          "invalid flags",
+			value:         "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-x1",
+			expectedError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := tracing.ParseTraceParent(testCase.value)
+			if testCase.expectedError {
+				if err == nil {
+					t.Errorf("expected an error but got traceparent %v", got)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %s", err)
+			}
+
+			if testCase.expected != got {
+				t.Errorf("expected traceparent %v but got %v", testCase.expected, got)
+			}
+		})
+	}
+}
+
+func TestTraceParent_String(t *testing.T) {
+	expected := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	traceParent := tracing.TraceParent{
+		TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:  "00f067aa0ba902b7",
+		Flags:   tracing.FlagSampled,
+	}
+
+	if got := traceParent.String(); expected != got {
+		t.Errorf("expected '%s' but got '%s'", expected, got)
+	}
+}
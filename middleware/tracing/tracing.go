@@ -0,0 +1,119 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis/middleware/writer"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// AttributeMethod is the span attribute containing the request method
+	AttributeMethod = "http.method"
+
+	// AttributeRoute is the span attribute containing the route template or the path if no route matched
+	AttributeRoute = "http.route"
+
+	// AttributeStatusCode is the span attribute containing the response status code
+	AttributeStatusCode = "http.status_code"
+)
+
+type tracing struct {
+	Exporter Exporter
+	Log      logrus.FieldLogger
+}
+
+// New returns a middleware creating a span per request. An incoming valid traceparent continues the trace, otherwise
+// a new sampled trace is started. The span is stored in the context and its traceparent is sent in the response
+// header. Sampled spans are exported after the request finished, the exporter can be nil.
+func New(log logrus.FieldLogger, exporter Exporter) mux.MiddlewareFunc {
+	mw := &tracing{Exporter: exporter, Log: log}
+	return mw.handler
+}
+
+func (t *tracing) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		span := startSpan(request)
+		request = request.WithContext(context.WithValue(request.Context(), ContextKeySpan, span))
+
+		Inject(w.Header(), span)
+
+		recorder := writer.NewRecorder(w)
+		panicked := true
+
+		// deferred to end the span of a panicking handler too, the panic keeps propagating to the recovery middleware
+		defer func() {
+			status := recorder.Status()
+			if panicked {
+				status = http.StatusInternalServerError
+			}
+
+			t.endSpan(request, span, status)
+		}()
+
+		next.ServeHTTP(recorder, request)
+
+		panicked = false
+	})
+}
+
+func startSpan(request *http.Request) *Span {
+	span := &Span{
+		SpanID: newID(spanIDLength),
+		Start:  time.Now(),
+		Status: StatusUnset,
+	}
+
+	parent, err := ParseTraceParent(request.Header.Get(HeaderTraceParent))
+	if err == nil {
+		span.TraceID = parent.TraceID
+		span.ParentSpanID = parent.SpanID
+		span.Flags = parent.Flags
+		span.TraceState = request.Header.Get(HeaderTraceState)
+	} else {
+		span.TraceID = newID(traceIDLength)
+		span.Flags = FlagSampled
+	}
+
+	span.Name = request.Method + " " + getRoute(request)
+	span.SetAttribute(AttributeMethod, request.Method)
+
+	return span
+}
+
+func (t *tracing) endSpan(request *http.Request, span *Span, status int) {
+	span.End = time.Now()
+	span.Status = StatusOK
+
+	if status >= http.StatusInternalServerError {
+		span.Status = StatusError
+	}
+
+	span.SetAttribute(AttributeRoute, getRoute(request))
+	span.SetAttribute(AttributeStatusCode, strconv.Itoa(status))
+
+	if t.Exporter == nil || !span.Sampled() {
+		return
+	}
+
+	if err := t.Exporter.Export(*span); err != nil {
+		NewLoggerFromContext(request.Context(), t.Log).Errorf("failed to export span: %s", err)
+	}
+}
+
+// getRoute returns the path template of the matched route. If no route matched, the path is returned.
+func getRoute(request *http.Request) string {
+	if route := mux.CurrentRoute(request); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+
+	return request.URL.Path
+}
@@ -0,0 +1,166 @@
+package tracing_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis/middleware/recovery"
+	"github.com/rebel-l/smis/middleware/tracing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestNew(t *testing.T) { // nolint: funlen
+	testCases := []struct {
+		name             string
+		traceParent      string
+		traceState       string
+		status           int
+		expectedTraceID  string
+		expectedParentID string
+		expectedStatus   tracing.Status
+		expectedExports  int
+	}{
+		{
+			name:            "new trace",
+			status:          http.StatusOK,
+			expectedStatus:  tracing.StatusOK,
+			expectedExports: 1,
+		},
+		{
+			name:             "continued trace",
+			traceParent:      "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			traceState:       "vendor=value",
+			status:           http.StatusInternalServerError,
+			expectedTraceID:  "4bf92f3577b34da6a3ce929d0e0e4736",
+			expectedParentID: "00f067aa0ba902b7",
+			expectedStatus:   tracing.StatusError,
+			expectedExports:  1,
+		},
+		{
+			name:             "not sampled",
+			traceParent:      "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+			status:           http.StatusOK,
+			expectedTraceID:  "4bf92f3577b34da6a3ce929d0e0e4736",
+			expectedParentID: "00f067aa0ba902b7",
+		},
+		{
+			name:            "invalid traceparent starts new trace",
+			traceParent:     "00-invalid-00f067aa0ba902b7-01",
+			status:          http.StatusOK,
+			expectedStatus:  tracing.StatusOK,
+			expectedExports: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			log, hook := test.NewNullLogger()
+			exporter := tracing.NewInMemoryExporter()
+
+			var span *tracing.Span
+
+			router := mux.NewRouter()
+			router.Use(tracing.New(log, exporter))
+			router.HandleFunc("/user/{id}", func(w http.ResponseWriter, request *http.Request) {
+				span = tracing.GetSpan(request.Context())
+				tracing.NewLoggerFromContext(request.Context(), log).Info("inside")
+				w.WriteHeader(testCase.status)
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/user/1", nil)
+			if testCase.traceParent != "" {
+				request.Header.Set(tracing.HeaderTraceParent, testCase.traceParent)
+				request.Header.Set(tracing.HeaderTraceState, testCase.traceState)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			if span == nil {
+				t.Fatal("expected span in context but got nil")
+			}
+
+			if testCase.expectedTraceID != "" && testCase.expectedTraceID != span.TraceID {
+				t.Errorf("expected trace id '%s' but got '%s'", testCase.expectedTraceID, span.TraceID)
+			}
+
+			if testCase.expectedParentID != span.ParentSpanID {
+				t.Errorf("expected parent id '%s' but got '%s'", testCase.expectedParentID, span.ParentSpanID)
+			}
+
+			if traceState := w.Header().Get(tracing.HeaderTraceState); testCase.traceState != traceState {
+				t.Errorf("expected tracestate '%s' but got '%s'", testCase.traceState, traceState)
+			}
+
+			got, err := tracing.ParseTraceParent(w.Header().Get(tracing.HeaderTraceParent))
+			if err != nil {
+				t.Fatalf("expected valid traceparent in response: %s", err)
+			}
+
+			if got.TraceID != span.TraceID || got.SpanID != span.SpanID {
+				t.Errorf("expected traceparent of span %s but got %s", span.TraceParent(), got)
+			}
+
+			entry := hook.LastEntry()
+			if entry.Data[tracing.LogFieldTraceID] != span.TraceID || entry.Data[tracing.LogFieldSpanID] != span.SpanID {
+				t.Errorf("expected log to contain trace and span id but got %v", entry.Data)
+			}
+
+			spans := exporter.Spans()
+			if testCase.expectedExports != len(spans) {
+				t.Fatalf("expected %d exported spans but got %d", testCase.expectedExports, len(spans))
+			}
+
+			if testCase.expectedExports == 0 {
+				return
+			}
+
+			if spans[0].Name != "GET /user/{id}" {
+				t.Errorf("expected span name 'GET /user/{id}' but got '%s'", spans[0].Name)
+			}
+
+			if testCase.expectedStatus != spans[0].Status {
+				t.Errorf("expected status '%s' but got '%s'", testCase.expectedStatus, spans[0].Status)
+			}
+
+			if spans[0].End.Before(spans[0].Start) {
+				t.Errorf("expected end %s not to be before start %s", spans[0].End, spans[0].Start)
+			}
+		})
+	}
+}
+
+func TestNew_Panic(t *testing.T) {
+	log, _ := test.NewNullLogger()
+	exporter := tracing.NewInMemoryExporter()
+
+	router := mux.NewRouter()
+	router.Use(recovery.New(log), tracing.New(log, exporter))
+	router.HandleFunc("/panic", func(_ http.ResponseWriter, _ *http.Request) {
+		panic("something went wrong")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected code %d but got %d", http.StatusInternalServerError, w.Code)
+	}
+
+	spans := exporter.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 exported span but got %d", len(spans))
+	}
+
+	if spans[0].Status != tracing.StatusError {
+		t.Errorf("expected status '%s' but got '%s'", tracing.StatusError, spans[0].Status)
+	}
+
+	if code := spans[0].Attributes[tracing.AttributeStatusCode]; code != "500" {
+		t.Errorf("expected status code '500' but got '%s'", code)
+	}
+}
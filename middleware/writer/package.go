@@ -0,0 +1,3 @@
+// Package writer provides a http.ResponseWriter which records the status code and size of a response. It is used by
+// middleware which needs to know what the handler responded.
+package writer
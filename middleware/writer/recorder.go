@@ -0,0 +1,92 @@
+package writer
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Recorder wraps a http.ResponseWriter and records status code and number of bytes written.
+type Recorder struct {
+	http.ResponseWriter
+	status      int
+	size        int
+	wroteHeader bool
+}
+
+// NewRecorder returns a Recorder wrapping the given writer. If the writer is a Recorder already, it is returned.
+func NewRecorder(writer http.ResponseWriter) *Recorder {
+	if recorder, ok := writer.(*Recorder); ok {
+		return recorder
+	}
+
+	return &Recorder{ResponseWriter: writer}
+}
+
+// WriteHeader records the status code and sends it. Only the first call is recorded.
+func (r *Recorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.status = code
+		r.wroteHeader = true
+	}
+
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Write records the number of bytes written. If no status code was sent before, it is 200.
+func (r *Recorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	n, err := r.ResponseWriter.Write(b)
+	r.size += n
+
+	return n, err
+}
+
+// Status returns the recorded status code. It is 200 if nothing was written, as the server sends this by default.
+func (r *Recorder) Status() int {
+	if !r.wroteHeader {
+		return http.StatusOK
+	}
+
+	return r.status
+}
+
+// Size returns the number of bytes written to the body.
+func (r *Recorder) Size() int {
+	return r.size
+}
+
+// WroteHeader returns true if the status code was sent already.
+func (r *Recorder) WroteHeader() bool {
+	return r.wroteHeader
+}
+
+// Flush sends buffered data to the client if the wrapped writer supports it.
+func (r *Recorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		if !r.wroteHeader {
+			r.WriteHeader(http.StatusOK)
+		}
+
+		flusher.Flush()
+	}
+}
+
+// Hijack lets the caller take over the connection if the wrapped writer supports it.
+func (r *Recorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer doesn't support hijacking")
+	}
+
+	return hijacker.Hijack()
+}
+
+// Unwrap returns the wrapped writer.
+func (r *Recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,65 @@
+package writer_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rebel-l/smis/middleware/writer"
+)
+
+func TestRecorder(t *testing.T) {
+	testCases := []struct {
+		name           string
+		write          func(w http.ResponseWriter)
+		expectedStatus int
+		expectedSize   int
+	}{
+		{
+			name:           "nothing written",
+			write:          func(_ http.ResponseWriter) {},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "body only",
+			write: func(w http.ResponseWriter) {
+				_, _ = w.Write([]byte("hello"))
+			},
+			expectedStatus: http.StatusOK,
+			expectedSize:   5,
+		},
+		{
+			name: "status and body",
+			write: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusCreated)
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte("hello"))
+				_, _ = w.Write([]byte(" world"))
+			},
+			expectedStatus: http.StatusCreated,
+			expectedSize:   11,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			recorder := writer.NewRecorder(httptest.NewRecorder())
+			testCase.write(recorder)
+
+			if testCase.expectedStatus != recorder.Status() {
+				t.Errorf("expected status %d but got %d", testCase.expectedStatus, recorder.Status())
+			}
+
+			if testCase.expectedSize != recorder.Size() {
+				t.Errorf("expected size %d but got %d", testCase.expectedSize, recorder.Size())
+			}
+		})
+	}
+}
+
+func TestNewRecorder_Twice(t *testing.T) {
+	recorder := writer.NewRecorder(httptest.NewRecorder())
+	if writer.NewRecorder(recorder) != recorder {
+		t.Error("expected recorder not to be wrapped twice")
+	}
+}
@@ -0,0 +1,293 @@
+package smis
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis/openapi"
+)
+
+// OpenAPIPathDefault is the default path of the OpenAPI endpoint
+const OpenAPIPathDefault = "/openapi"
+
+// nolint: gochecknoglobals
+var pathVariableRegex = regexp.MustCompile(`{([^}:]+)(:[^}]*)?}`)
+
+// Endpoint describes an endpoint for the OpenAPI document. Parameters is a struct with path, query and header tags
+// as used by Request.Bind(), RequestBody and the payloads of Responses are instances of the types sent as JSON.
+type Endpoint struct {
+	Summary     string
+	Description string
+	OperationID string
+	Tags        []string
+	Deprecated  bool
+	Parameters  interface{}
+	RequestBody interface{}
+	Responses   map[int]EndpointResponse
+}
+
+// EndpointResponse describes a response of an endpoint. Payload is nil for responses without body.
+type EndpointResponse struct {
+	Description string
+	Payload     interface{}
+}
+
+// EndpointOption adds metadata to an endpoint registered with one of the RegisterEndpoint methods.
+type EndpointOption func(endpoint *Endpoint)
+
+// WithSummary sets the summary of the endpoint.
+func WithSummary(summary string) EndpointOption {
+	return func(endpoint *Endpoint) {
+		endpoint.Summary = summary
+	}
+}
+
+// WithDescription sets the description of the endpoint.
+func WithDescription(description string) EndpointOption {
+	return func(endpoint *Endpoint) {
+		endpoint.Description = description
+	}
+}
+
+// WithOperationID sets the unique operation ID of the endpoint.
+func WithOperationID(id string) EndpointOption {
+	return func(endpoint *Endpoint) {
+		endpoint.OperationID = id
+	}
+}
+
+// WithTags adds tags to the endpoint.
+func WithTags(tags ...string) EndpointOption {
+	return func(endpoint *Endpoint) {
+		endpoint.Tags = append(endpoint.Tags, tags...)
+	}
+}
+
+// WithDeprecated marks the endpoint as deprecated.
+func WithDeprecated() EndpointOption {
+	return func(endpoint *Endpoint) {
+		endpoint.Deprecated = true
+	}
+}
+
+// WithParameters sets the struct describing the parameters of the endpoint, see Request.Bind().
+func WithParameters(parameters interface{}) EndpointOption {
+	return func(endpoint *Endpoint) {
+		endpoint.Parameters = parameters
+	}
+}
+
+// WithRequestBody sets the type of the JSON request body, see Request.DecodeJSON().
+func WithRequestBody(body interface{}) EndpointOption {
+	return func(endpoint *Endpoint) {
+		endpoint.RequestBody = body
+	}
+}
+
+// WithResponse adds a response of the endpoint. Payload is the type of the JSON body or nil.
+func WithResponse(status int, description string, payload interface{}) EndpointOption {
+	return func(endpoint *Endpoint) {
+		if endpoint.Responses == nil {
+			endpoint.Responses = make(map[int]EndpointResponse)
+		}
+
+		endpoint.Responses[status] = EndpointResponse{Description: description, Payload: payload}
+	}
+}
+
+type chainSecurity struct {
+	name   string
+	scheme openapi.SecurityScheme
+	scopes []string
+}
+
+// AddSecurityScheme documents that all endpoints of the chain require the security scheme. Call it multiple times to
+// require several schemes. Schemes of the default chain are required by all endpoints.
+func (s *Service) AddSecurityScheme(chain, name string, scheme openapi.SecurityScheme, scopes ...string) {
+	if s.security == nil {
+		s.security = make(map[string][]chainSecurity)
+	}
+
+	s.security[chain] = append(s.security[chain], chainSecurity{name: name, scheme: scheme, scopes: scopes})
+}
+
+// OpenAPI generates the OpenAPI document of all routes with methods. Endpoints registered without options are
+// documented with their path parameters and a default response only.
+func (s *Service) OpenAPI(info openapi.Info) (*openapi.Document, error) {
+	doc := openapi.NewDocument(info)
+	generator := newSchemaGenerator(doc)
+
+	for _, security := range s.security {
+		for _, sec := range security {
+			doc.AddSecurityScheme(sec.name, sec.scheme)
+		}
+	}
+
+	err := s.Router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		methods, err := route.GetMethods()
+		if err != nil || s.fileServers[route] {
+			return nil // sub routers and file servers are not documented
+		}
+
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+
+		for _, method := range methods {
+			operation := s.newOperation(generator, route, template)
+			doc.AddOperation(pathVariableRegex.ReplaceAllString(template, "{$1}"), method, operation)
+		}
+
+		return nil
+	})
+
+	return doc, err
+}
+
+func (s *Service) newOperation(generator *schemaGenerator, route *mux.Route, template string) *openapi.Operation {
+	endpoint := s.endpoints[route]
+	if endpoint == nil {
+		endpoint = &Endpoint{}
+	}
+
+	operation := &openapi.Operation{
+		Tags:        endpoint.Tags,
+		Summary:     endpoint.Summary,
+		Description: endpoint.Description,
+		OperationID: endpoint.OperationID,
+		Deprecated:  endpoint.Deprecated,
+		Responses:   make(map[string]*openapi.Response),
+	}
+
+	if endpoint.Parameters != nil {
+		operation.Parameters = generator.parameters(reflect.TypeOf(endpoint.Parameters))
+	}
+
+	operation.Parameters = addPathParameters(operation.Parameters, template)
+
+	if endpoint.RequestBody != nil {
+		operation.RequestBody = &openapi.RequestBody{
+			Required: true,
+			Content: map[string]openapi.MediaType{
+				HeaderContentTypeJSON: {Schema: generator.schema(reflect.TypeOf(endpoint.RequestBody))},
+			},
+		}
+	}
+
+	for status, response := range endpoint.Responses {
+		r := &openapi.Response{Description: response.Description}
+		if r.Description == "" {
+			r.Description = http.StatusText(status)
+		}
+
+		if response.Payload != nil {
+			r.Content = map[string]openapi.MediaType{
+				HeaderContentTypeJSON: {Schema: generator.schema(reflect.TypeOf(response.Payload))},
+			}
+		}
+
+		operation.Responses[strconv.Itoa(status)] = r
+	}
+
+	if len(operation.Responses) == 0 {
+		operation.Responses["default"] = &openapi.Response{Description: "default response"}
+	}
+
+	operation.Security = s.securityOfChain(s.chainOfRoute(route))
+
+	return operation
+}
+
+// securityOfChain returns the security requirement of the chain. As the middleware of the default chain applies to
+// all chains, its schemes are required too.
+func (s *Service) securityOfChain(chain string) []openapi.SecurityRequirement {
+	requirement := make(openapi.SecurityRequirement)
+
+	for _, sec := range s.security[MiddlewareChainDefault] {
+		requirement[sec.name] = scopesOrEmpty(sec.scopes)
+	}
+
+	if chain != MiddlewareChainDefault {
+		for _, sec := range s.security[chain] {
+			requirement[sec.name] = scopesOrEmpty(sec.scopes)
+		}
+	}
+
+	if len(requirement) == 0 {
+		return nil
+	}
+
+	return []openapi.SecurityRequirement{requirement}
+}
+
+// addPathParameters adds the variables of the path template not declared as parameters yet.
+func addPathParameters(parameters []openapi.Parameter, template string) []openapi.Parameter {
+	for _, match := range pathVariableRegex.FindAllStringSubmatch(template, -1) {
+		declared := false
+
+		for _, p := range parameters {
+			if p.In == TagPath && p.Name == match[1] {
+				declared = true
+				break
+			}
+		}
+
+		if !declared {
+			parameters = append(parameters, openapi.Parameter{
+				Name:     match[1],
+				In:       TagPath,
+				Required: true,
+				Schema:   &openapi.Schema{Type: "string"},
+			})
+		}
+	}
+
+	sort.SliceStable(parameters, func(i, j int) bool {
+		return parameters[i].In == TagPath && parameters[j].In != TagPath
+	})
+
+	return parameters
+}
+
+func scopesOrEmpty(scopes []string) []string {
+	if scopes == nil {
+		return []string{}
+	}
+
+	return scopes
+}
+
+// WithOpenAPI registers an endpoint at the default chain serving the OpenAPI document as JSON or YAML depending on
+// the Accept header. If path is empty, OpenAPIPathDefault is used.
+func (s *Service) WithOpenAPI(path string, info openapi.Info) (*Service, error) {
+	if path == "" {
+		path = OpenAPIPathDefault
+	}
+
+	_, err := s.RegisterEndpoint(path, http.MethodGet, func(writer http.ResponseWriter, request *http.Request) {
+		log := s.NewLogForRequestID(request.Context())
+		response := &Response{Log: log, Encoders: NewEncoders(JSONEncoder{}, YAMLEncoder{})}
+
+		doc, err := s.OpenAPI(info)
+		if err != nil {
+			log.Errorf("failed to generate OpenAPI document: %v", err)
+			response.WriteProblem(writer, request, NewProblem(http.StatusInternalServerError, ""))
+
+			return
+		}
+
+		response.Write(writer, request, http.StatusOK, doc)
+	}, WithSummary("OpenAPI document"), WithTags("meta"))
+	if err != nil {
+		return s, fmt.Errorf("failed to register OpenAPI endpoint: %w", err)
+	}
+
+	return s, nil
+}
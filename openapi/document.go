@@ -0,0 +1,177 @@
+package openapi
+
+import "strings"
+
+const (
+	// Version is the OpenAPI version of the documents
+	Version = "3.0.3"
+
+	// RefPrefixSchemas is the prefix of references to schemas in the components
+	RefPrefixSchemas = "#/components/schemas/"
+)
+
+// Document is the root of an OpenAPI document.
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Servers    []Server              `json:"servers,omitempty"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components *Components           `json:"components,omitempty"`
+	Security   []SecurityRequirement `json:"security,omitempty"`
+	Tags       []Tag                 `json:"tags,omitempty"`
+}
+
+// NewDocument returns a document with the given info and no paths.
+func NewDocument(info Info) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+	}
+}
+
+// AddOperation adds the operation for the path and method. An existing operation is replaced.
+func (d *Document) AddOperation(path, method string, operation *Operation) {
+	item, ok := d.Paths[path]
+	if !ok {
+		item = make(PathItem)
+		d.Paths[path] = item
+	}
+
+	item[strings.ToLower(method)] = operation
+}
+
+// AddSchema adds a named schema to the components.
+func (d *Document) AddSchema(name string, schema *Schema) {
+	d.components().Schemas[name] = schema
+}
+
+// AddSecurityScheme adds a named security scheme to the components.
+func (d *Document) AddSecurityScheme(name string, scheme SecurityScheme) {
+	d.components().SecuritySchemes[name] = scheme
+}
+
+func (d *Document) components() *Components {
+	if d.Components == nil {
+		d.Components = &Components{}
+	}
+
+	if d.Components.Schemas == nil {
+		d.Components.Schemas = make(map[string]*Schema)
+	}
+
+	if d.Components.SecuritySchemes == nil {
+		d.Components.SecuritySchemes = make(map[string]SecurityScheme)
+	}
+
+	return d.Components
+}
+
+// Info provides metadata about the API.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server is a server providing the API.
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// Tag adds metadata to a tag used by operations.
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem contains the operations of a path by lower case HTTP method.
+type PathItem map[string]*Operation
+
+// Operation describes a single API operation on a path. Responses are keyed by status code or "default".
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+	Security    []SecurityRequirement `json:"security,omitempty"`
+}
+
+// Parameter describes a single operation parameter. In is one of path, query, header or cookie.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+// RequestBody describes the body of a request by media type.
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// Response describes a single response of an operation.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType provides the schema of a media type.
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Components holds reusable schemas and security schemes.
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// Schema describes a data type. Ref references a schema in the components, all other fields are ignored then.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *uint64            `json:"minLength,omitempty"`
+	MaxLength            *uint64            `json:"maxLength,omitempty"`
+	MinItems             *uint64            `json:"minItems,omitempty"`
+	MaxItems             *uint64            `json:"maxItems,omitempty"`
+}
+
+// NewRef returns a schema referencing the named schema in the components.
+func NewRef(name string) *Schema {
+	return &Schema{Ref: RefPrefixSchemas + name}
+}
+
+// SecurityScheme describes a security scheme. Type is one of apiKey, http, oauth2 or openIdConnect.
+type SecurityScheme struct {
+	Type             string `json:"type"`
+	Description      string `json:"description,omitempty"`
+	Name             string `json:"name,omitempty"`
+	In               string `json:"in,omitempty"`
+	Scheme           string `json:"scheme,omitempty"`
+	BearerFormat     string `json:"bearerFormat,omitempty"`
+	OpenIDConnectURL string `json:"openIdConnectUrl,omitempty"`
+}
+
+// SecurityRequirement lists the required security schemes by name with their scopes.
+type SecurityRequirement map[string][]string
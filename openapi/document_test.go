@@ -0,0 +1,46 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rebel-l/smis/openapi"
+)
+
+func TestDocument(t *testing.T) {
+	doc := openapi.NewDocument(openapi.Info{Title: "Users", Version: "1.0.0"})
+	doc.AddOperation("/users/{id}", "GET", &openapi.Operation{
+		Summary: "Get user",
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "OK",
+				Content:     map[string]openapi.MediaType{"application/json": {Schema: openapi.NewRef("User")}},
+			},
+		},
+		Security: []openapi.SecurityRequirement{{"bearer": []string{}}},
+	})
+	doc.AddOperation("/users/{id}", "DELETE", &openapi.Operation{
+		Responses: map[string]*openapi.Response{"204": {Description: "No Content"}},
+	})
+	doc.AddSchema("User", &openapi.Schema{
+		Type:       "object",
+		Properties: map[string]*openapi.Schema{"name": {Type: "string"}},
+		Required:   []string{"name"},
+	})
+	doc.AddSecurityScheme("bearer", openapi.SecurityScheme{Type: "http", Scheme: "bearer"})
+
+	got, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("failed to encode document: %s", err)
+	}
+
+	expected := `{"openapi":"3.0.3","info":{"title":"Users","version":"1.0.0"},"paths":{"/users/{id}":{"delete":` +
+		`{"responses":{"204":{"description":"No Content"}}},"get":{"summary":"Get user","responses":{"200":` +
+		`{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/User"}}}}},` +
+		`"security":[{"bearer":[]}]}}},"components":{"schemas":{"User":{"type":"object","properties":{"name":` +
+		`{"type":"string"}},"required":["name"]}},"securitySchemes":{"bearer":{"type":"http","scheme":"bearer"}}}}`
+
+	if expected != string(got) {
+		t.Errorf("expected document\n%s\nbut got\n%s", expected, got)
+	}
+}
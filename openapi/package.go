@@ -0,0 +1,3 @@
+// Package openapi provides the types of an OpenAPI 3 document. Documents are encoded with encoding/json, for YAML
+// convert the JSON output.
+package openapi
@@ -0,0 +1,197 @@
+package smis_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis"
+	"github.com/rebel-l/smis/openapi"
+
+	"github.com/sirupsen/logrus"
+)
+
+type userOpenAPITest struct {
+	ID   string `json:"id"`
+	Name string `json:"name" validate:"required"`
+}
+
+func newOpenAPIService(t *testing.T) *smis.Service {
+	t.Helper()
+
+	service, err := smis.NewService(&http.Server{}, mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	handler := func(_ http.ResponseWriter, _ *http.Request) {}
+
+	// registered before the restricted chain exists, so it belongs to the default chain despite of its prefix
+	if _, err = service.RegisterEndpoint("/restricted/status", http.MethodGet, handler); err != nil {
+		t.Fatalf("failed to register endpoint: %s", err)
+	}
+
+	_, err = service.RegisterEndpointToRestictedChain("/users/{id:[0-9]+}", http.MethodGet, handler,
+		smis.WithSummary("Get user"),
+		smis.WithTags("users"),
+		smis.WithOperationID("getUser"),
+		smis.WithResponse(http.StatusOK, "", userOpenAPITest{}),
+		smis.WithResponse(http.StatusNotFound, "user not found", smis.Problem{}),
+	)
+	if err != nil {
+		t.Fatalf("failed to register endpoint: %s", err)
+	}
+
+	_, err = service.RegisterEndpointToPublicChain("/users", http.MethodPost, handler,
+		smis.WithDescription("Creates a user"),
+		smis.WithDeprecated(),
+		smis.WithParameters(struct {
+			DryRun bool `query:"dry_run"`
+		}{}),
+		smis.WithRequestBody(&userOpenAPITest{}),
+		smis.WithResponse(http.StatusCreated, "", nil),
+	)
+	if err != nil {
+		t.Fatalf("failed to register endpoint: %s", err)
+	}
+
+	if _, err = service.RegisterEndpoint("/ping", http.MethodGet, handler); err != nil {
+		t.Fatalf("failed to register endpoint: %s", err)
+	}
+
+	service.AddSecurityScheme(smis.MiddlewareChainRestricted, "bearer",
+		openapi.SecurityScheme{Type: "http", Scheme: "bearer"}, "users:read")
+
+	return service
+}
+
+func TestService_OpenAPI(t *testing.T) { // nolint: funlen
+	service := newOpenAPIService(t)
+
+	doc, err := service.OpenAPI(openapi.Info{Title: "Users", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("expected no error but got: %s", err)
+	}
+
+	testCases := []struct {
+		name     string
+		path     string
+		method   string
+		expected string
+	}{
+		{
+			name:   "restricted with path parameter and security",
+			path:   "/restricted/users/{id}",
+			method: "get",
+			expected: `{"tags":["users"],"summary":"Get user","operationId":"getUser","parameters":[{"name":"id",` +
+				`"in":"path","required":true,"schema":{"type":"string"}}],"responses":{"200":{"description":"OK",` +
+				`"content":{"application/json":{"schema":{"$ref":"#/components/schemas/userOpenAPITest"}}}},` +
+				`"404":{"description":"user not found","content":{"application/json":{"schema":{"$ref":` +
+				`"#/components/schemas/Problem"}}}}},"security":[{"bearer":["users:read"]}]}`,
+		},
+		{
+			name:   "public with body and parameters",
+			path:   "/public/users",
+			method: "post",
+			expected: `{"description":"Creates a user","parameters":[{"name":"dry_run","in":"query","schema":` +
+				`{"type":"boolean"}}],"requestBody":{"required":true,"content":{"application/json":{"schema":` +
+				`{"$ref":"#/components/schemas/userOpenAPITest"}}}},"responses":{"201":{"description":"Created"}},` +
+				`"deprecated":true}`,
+		},
+		{
+			name:     "default chain with prefix of restricted chain",
+			path:     "/restricted/status",
+			method:   "get",
+			expected: `{"responses":{"default":{"description":"default response"}}}`,
+		},
+		{
+			name:     "without options",
+			path:     "/ping",
+			method:   "get",
+			expected: `{"responses":{"default":{"description":"default response"}}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			operation, ok := doc.Paths[testCase.path][testCase.method]
+			if !ok {
+				t.Fatalf("expected operation %s %s in document", testCase.method, testCase.path)
+			}
+
+			got, err := json.Marshal(operation)
+			if err != nil {
+				t.Fatalf("failed to encode operation: %s", err)
+			}
+
+			if testCase.expected != string(got) {
+				t.Errorf("expected operation\n%s\nbut got\n%s", testCase.expected, got)
+			}
+		})
+	}
+
+	if _, ok := doc.Components.SecuritySchemes["bearer"]; !ok {
+		t.Error("expected security scheme bearer in components")
+	}
+
+	if _, ok := doc.Components.Schemas["userOpenAPITest"]; !ok {
+		t.Error("expected schema userOpenAPITest in components")
+	}
+}
+
+func TestService_WithOpenAPI(t *testing.T) {
+	service := newOpenAPIService(t)
+
+	if _, err := service.WithOpenAPI("", openapi.Info{Title: "Users", Version: "1.0.0"}); err != nil {
+		t.Fatalf("expected no error but got: %s", err)
+	}
+
+	testCases := []struct {
+		name                string
+		accept              string
+		expectedContentType string
+		expectedPrefix      string
+	}{
+		{
+			name:                "json",
+			expectedContentType: smis.HeaderContentTypeJSON,
+			expectedPrefix:      `{"openapi":"3.0.3","info":{"title":"Users","version":"1.0.0"}`,
+		},
+		{
+			name:                "yaml",
+			accept:              smis.HeaderContentTypeYAML,
+			expectedContentType: smis.HeaderContentTypeYAML,
+			expectedPrefix:      "components:\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, smis.OpenAPIPathDefault, nil)
+			request.Header.Set(smis.HeaderKeyAccept, testCase.accept)
+
+			w := httptest.NewRecorder()
+			service.Router.ServeHTTP(w, request)
+
+			if http.StatusOK != w.Code {
+				t.Fatalf("expected status %d but got %d", http.StatusOK, w.Code)
+			}
+
+			if contentType := w.Header().Get(smis.HeaderKeyContentType); contentType != testCase.expectedContentType {
+				t.Errorf("expected content type '%s' but got '%s'", testCase.expectedContentType, contentType)
+			}
+
+			if !strings.HasPrefix(w.Body.String(), testCase.expectedPrefix) {
+				t.Errorf("expected body to start with '%s' but got '%s'", testCase.expectedPrefix, w.Body.String())
+			}
+
+			if !strings.Contains(w.Body.String(), "/restricted/users/{id}") {
+				t.Errorf("expected body to contain the registered routes but got '%s'", w.Body.String())
+			}
+		})
+	}
+}
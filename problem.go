@@ -0,0 +1,126 @@
+package smis
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/rebel-l/smis/middleware/requestid"
+)
+
+const (
+	// HeaderContentTypeProblemJSON represent the value for content type problem JSON (RFC 7807) in the header
+	HeaderContentTypeProblemJSON = "application/problem+json"
+
+	// ProblemTypeDefault is the problem type if no type is given, the title should be the HTTP status text then
+	ProblemTypeDefault = "about:blank"
+)
+
+// Problem represents the problem details of an error response as defined by RFC 7807. Extensions are added as
+// additional members to the JSON object, extensions named like a standard member are dropped even if it is empty.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	RequestID  string
+	Extensions map[string]interface{}
+}
+
+// NewProblem returns a problem of default type with the status text as title.
+func NewProblem(status int, detail string) Problem {
+	return Problem{
+		Type:   ProblemTypeDefault,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// MarshalJSON encodes the problem with its extensions to a flat JSON object.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]interface{}, len(p.Extensions)+6)
+
+	for key, value := range p.Extensions {
+		if !isProblemMember(key) {
+			members[key] = value
+		}
+	}
+
+	members["type"] = p.Type
+	members["title"] = p.Title
+	members["status"] = p.Status
+
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+
+	if p.RequestID != "" {
+		members["request_id"] = p.RequestID
+	}
+
+	return json.Marshal(members)
+}
+
+// isProblemMember returns true if the name is reserved for a standard member, even if the member is empty.
+func isProblemMember(name string) bool {
+	switch name {
+	case "type", "title", "status", "detail", "instance", "request_id":
+		return true
+	default:
+		return false
+	}
+}
+
+// WriteProblem sends the problem as problem JSON. Missing fields are filled from the request: the instance with the
+// request URI and the request ID with the one generated by the requestid middleware. Type and title default to
+// about:blank and the status text.
+func (r *Response) WriteProblem(writer http.ResponseWriter, request *http.Request, problem Problem) {
+	if writer == nil {
+		r.logError("writer is nil")
+		return
+	}
+
+	if problem.Status == 0 {
+		problem.Status = http.StatusInternalServerError
+	}
+
+	if problem.Type == "" {
+		problem.Type = ProblemTypeDefault
+	}
+
+	if problem.Title == "" && problem.Type == ProblemTypeDefault {
+		problem.Title = http.StatusText(problem.Status)
+	}
+
+	if request != nil {
+		if problem.Instance == "" {
+			problem.Instance = request.URL.RequestURI()
+		}
+
+		if problem.RequestID == "" {
+			problem.RequestID = requestid.GetID(request.Context())
+		}
+	}
+
+	response, err := json.Marshal(problem)
+	if err != nil {
+		r.WriteJSON(writer, http.StatusInternalServerError, errorJSON{
+			Error: fmt.Sprintf("failed to encode problem: %v", err),
+		})
+
+		return
+	}
+
+	writer.Header().Set(HeaderKeyContentType, HeaderContentTypeProblemJSON)
+	writer.WriteHeader(problem.Status)
+
+	if _, err := writer.Write(response); err != nil {
+		r.logError(fmt.Sprintf("failed to write response: %v", err))
+	}
+}
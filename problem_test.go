@@ -0,0 +1,133 @@
+package smis_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/rebel-l/smis"
+	"github.com/rebel-l/smis/middleware/requestid"
+)
+
+func TestResponse_WriteProblem(t *testing.T) { // nolint: funlen
+	request := httptest.NewRequest(http.MethodGet, "/user/1?expand=true", nil)
+	request = request.WithContext(context.WithValue(request.Context(), requestid.ContextKeyRequestID, "abc"))
+
+	testCases := []struct {
+		name         string
+		actual       *smis.Response
+		request      *http.Request
+		problem      smis.Problem
+		expectedCode int
+		expectedBody map[string]interface{}
+	}{
+		{
+			name:         "defaults from request",
+			request:      request,
+			problem:      smis.Problem{Status: http.StatusNotFound, Detail: "user 1 not found"},
+			expectedCode: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"type":       "about:blank",
+				"title":      "Not Found",
+				"status":     float64(http.StatusNotFound),
+				"detail":     "user 1 not found",
+				"instance":   "/user/1?expand=true",
+				"request_id": "abc",
+			},
+		},
+		{
+			name:    "custom type with extensions",
+			actual:  &smis.Response{},
+			request: request,
+			problem: smis.Problem{
+				Type:     "https://example.com/probs/out-of-credit",
+				Title:    "You do not have enough credit.",
+				Status:   http.StatusForbidden,
+				Instance: "/account/12345/msgs/abc",
+				Extensions: map[string]interface{}{
+					"balance": 30,
+					"status":  "overwritten",
+				},
+			},
+			expectedCode: http.StatusForbidden,
+			expectedBody: map[string]interface{}{
+				"type":       "https://example.com/probs/out-of-credit",
+				"title":      "You do not have enough credit.",
+				"status":     float64(http.StatusForbidden),
+				"instance":   "/account/12345/msgs/abc",
+				"request_id": "abc",
+				"balance":    float64(30),
+			},
+		},
+		{
+			name:         "without request and status",
+			actual:       &smis.Response{},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"type":   "about:blank",
+				"title":  "Internal Server Error",
+				"status": float64(http.StatusInternalServerError),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			testCase.actual.WriteProblem(w, testCase.request, testCase.problem)
+
+			if testCase.expectedCode != w.Code {
+				t.Errorf("expected code %d but got %d", testCase.expectedCode, w.Code)
+			}
+
+			contentType := w.Header().Get(smis.HeaderKeyContentType)
+			if contentType != smis.HeaderContentTypeProblemJSON {
+				t.Errorf("expected content type '%s' but got '%s'", smis.HeaderContentTypeProblemJSON, contentType)
+			}
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode body: %s", err)
+			}
+
+			if !reflect.DeepEqual(testCase.expectedBody, body) {
+				t.Errorf("expected body %v but got %v", testCase.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestProblem_MarshalJSON_ReservedExtensions(t *testing.T) {
+	problem := smis.NewProblem(http.StatusNotFound, "")
+	problem.Extensions = map[string]interface{}{
+		"type":       "spoof",
+		"detail":     "spoof",
+		"instance":   "spoof",
+		"request_id": "spoof",
+		"balance":    float64(30),
+	}
+
+	body, err := json.Marshal(problem)
+	if err != nil {
+		t.Fatalf("failed to encode problem: %s", err)
+	}
+
+	var actual map[string]interface{}
+	if err = json.Unmarshal(body, &actual); err != nil {
+		t.Fatalf("failed to decode problem: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"type":    "about:blank",
+		"title":   "Not Found",
+		"status":  float64(http.StatusNotFound),
+		"balance": float64(30),
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected problem %v but got %v", expected, actual)
+	}
+}
@@ -0,0 +1,166 @@
+package smis
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MaxBodySizeDefault is the default maximum size of a request body in bytes
+const MaxBodySizeDefault = 1 << 20
+
+// Request provides functions to read http requests. MaxBodySize limits the size of the body in bytes, if zero
+// MaxBodySizeDefault is used. If DisallowUnknownFields is true, decoding fails for fields not existing in the target.
+type Request struct {
+	Log                   logrus.FieldLogger
+	MaxBodySize           int64
+	DisallowUnknownFields bool
+}
+
+// RequestError describes why a request couldn't be processed. Status is the HTTP status code to respond.
+type RequestError struct {
+	Status int
+	Detail string
+	Fields []FieldError
+}
+
+// Error returns the detail of the error.
+func (e *RequestError) Error() string {
+	return e.Detail
+}
+
+// Problem returns the problem to respond, the field errors are added as extension "errors".
+func (e *RequestError) Problem() Problem {
+	problem := NewProblem(e.Status, e.Detail)
+	if len(e.Fields) > 0 {
+		problem.Extensions = map[string]interface{}{"errors": e.Fields}
+	}
+
+	return problem
+}
+
+// DecodeJSON decodes the JSON body of the request into dst and validates it, see ValidateStruct(). The returned
+// error is a *RequestError if the request is invalid: 415 for a wrong content type, 413 for a too large body, 400 for
+// malformed JSON and 422 if validation fails.
+func (r *Request) DecodeJSON(request *http.Request, dst interface{}) error {
+	if err := checkContentTypeJSON(request.Header.Get(HeaderKeyContentType)); err != nil {
+		return err
+	}
+
+	body, err := r.readBody(request)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if r.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	if err = decoder.Decode(dst); err != nil {
+		return newDecodeError(err)
+	}
+
+	if decoder.More() {
+		return &RequestError{Status: http.StatusBadRequest, Detail: "request body must contain a single JSON value"}
+	}
+
+	fields, err := ValidateStruct(dst)
+	if err != nil {
+		r.logError(fmt.Sprintf("failed to validate request body: %v", err))
+		return err
+	}
+
+	if len(fields) > 0 {
+		return &RequestError{Status: http.StatusUnprocessableEntity, Detail: "validation failed", Fields: fields}
+	}
+
+	return nil
+}
+
+func (r *Request) readBody(request *http.Request) ([]byte, error) {
+	if request.Body == nil {
+		return nil, &RequestError{Status: http.StatusBadRequest, Detail: "request body is empty"}
+	}
+
+	maxBodySize := r.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = MaxBodySizeDefault
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(request.Body, maxBodySize+1))
+	if err != nil {
+		return nil, &RequestError{Status: http.StatusBadRequest, Detail: fmt.Sprintf("failed to read body: %v", err)}
+	}
+
+	if int64(len(body)) > maxBodySize {
+		return nil, &RequestError{
+			Status: http.StatusRequestEntityTooLarge,
+			Detail: fmt.Sprintf("request body must not be larger than %d bytes", maxBodySize),
+		}
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, &RequestError{Status: http.StatusBadRequest, Detail: "request body is empty"}
+	}
+
+	return body, nil
+}
+
+func (r *Request) logError(msg string) {
+	if r == nil || r.Log == nil {
+		return
+	}
+
+	r.Log.Error(msg)
+}
+
+func checkContentTypeJSON(contentType string) error {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil && (mediaType == HeaderContentTypeJSON || strings.HasSuffix(mediaType, "+json")) {
+		return nil
+	}
+
+	return &RequestError{
+		Status: http.StatusUnsupportedMediaType,
+		Detail: fmt.Sprintf("content type must be %s but is '%s'", HeaderContentTypeJSON, contentType),
+	}
+}
+
+func newDecodeError(err error) error {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		return &RequestError{
+			Status: http.StatusBadRequest,
+			Detail: fmt.Sprintf("malformed JSON at position %d: %v", syntaxErr.Offset, err),
+		}
+	case errors.As(err, &typeErr):
+		return &RequestError{
+			Status: http.StatusBadRequest,
+			Detail: "request body contains invalid types",
+			Fields: []FieldError{{
+				Field:   typeErr.Field,
+				Rule:    "type",
+				Message: fmt.Sprintf("must be of type %s", typeErr.Type),
+			}},
+		}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return &RequestError{Status: http.StatusBadRequest, Detail: "malformed JSON: unexpected end of body"}
+	default:
+		// unknown fields are reported with an error message only
+		return &RequestError{Status: http.StatusBadRequest, Detail: fmt.Sprintf("invalid JSON: %v", err)}
+	}
+}
@@ -0,0 +1,136 @@
+package smis_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rebel-l/smis"
+)
+
+type createUserTest struct {
+	Name string `json:"name" validate:"required,min=3"`
+	Age  int    `json:"age"`
+}
+
+func TestRequest_DecodeJSON(t *testing.T) { // nolint: funlen
+	testCases := []struct {
+		name           string
+		actual         *smis.Request
+		contentType    string
+		body           string
+		expected       createUserTest
+		expectedStatus int
+		expectedFields []smis.FieldError
+	}{
+		{
+			name:        "success",
+			actual:      &smis.Request{},
+			contentType: "application/json; charset=utf-8",
+			body:        `{"name":"Herbert","age":42,"unknown":true}`,
+			expected:    createUserTest{Name: "Herbert", Age: 42},
+		},
+		{
+			name:        "success with json suffix",
+			actual:      &smis.Request{DisallowUnknownFields: true},
+			contentType: "application/merge-patch+json",
+			body:        `{"name":"Herbert"}`,
+			expected:    createUserTest{Name: "Herbert"},
+		},
+		{
+			name:           "wrong content type",
+			actual:         &smis.Request{},
+			contentType:    "text/plain",
+			body:           `{"name":"Herbert"}`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "too large",
+			actual:         &smis.Request{MaxBodySize: 10},
+			contentType:    smis.HeaderContentTypeJSON,
+			body:           `{"name":"Herbert"}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "empty",
+			actual:         &smis.Request{},
+			contentType:    smis.HeaderContentTypeJSON,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed",
+			actual:         &smis.Request{},
+			contentType:    smis.HeaderContentTypeJSON,
+			body:           `{"name":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "multiple values",
+			actual:         &smis.Request{},
+			contentType:    smis.HeaderContentTypeJSON,
+			body:           `{"name":"Herbert"}{"name":"Hans"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown field",
+			actual:         &smis.Request{DisallowUnknownFields: true},
+			contentType:    smis.HeaderContentTypeJSON,
+			body:           `{"name":"Herbert","unknown":true}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "wrong type",
+			actual:         &smis.Request{},
+			contentType:    smis.HeaderContentTypeJSON,
+			body:           `{"name":"Herbert","age":"old"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []smis.FieldError{{Field: "age", Rule: "type", Message: "must be of type int"}},
+		},
+		{
+			name:           "validation fails",
+			actual:         &smis.Request{},
+			contentType:    smis.HeaderContentTypeJSON,
+			body:           `{"name":"He"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedFields: []smis.FieldError{{Field: "name", Rule: "min", Message: "length must be at least 3"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/user", strings.NewReader(testCase.body))
+			request.Header.Set(smis.HeaderKeyContentType, testCase.contentType)
+
+			var got createUserTest
+
+			err := testCase.actual.DecodeJSON(request, &got)
+			if testCase.expectedStatus == 0 {
+				if err != nil {
+					t.Fatalf("expected no error but got: %s", err)
+				}
+
+				if testCase.expected != got {
+					t.Errorf("expected %v but got %v", testCase.expected, got)
+				}
+
+				return
+			}
+
+			var requestErr *smis.RequestError
+			if !errors.As(err, &requestErr) {
+				t.Fatalf("expected a request error but got: %v", err)
+			}
+
+			if testCase.expectedStatus != requestErr.Status {
+				t.Errorf("expected status %d but got %d: %s", testCase.expectedStatus, requestErr.Status, requestErr)
+			}
+
+			if !reflect.DeepEqual(testCase.expectedFields, requestErr.Fields) {
+				t.Errorf("expected field errors %v but got %v", testCase.expectedFields, requestErr.Fields)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package smis
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -16,9 +17,11 @@ const (
 	HeaderContentTypeJSON = "application/json"
 )
 
-//Response provides functions to write http responses.
+//Response provides functions to write http responses. Encoders are used for content negotiation by Write(), if nil
+//the DefaultEncoders are used.
 type Response struct {
-	Log logrus.FieldLogger
+	Log      logrus.FieldLogger
+	Encoders *Encoders
 }
 
 func (r *Response) logError(msg string) {
@@ -59,6 +62,73 @@ func (r *Response) WriteJSON(writer http.ResponseWriter, code int, payload inter
 	}
 }
 
+//Write sends a response with given code and payload encoded in the format the client accepts best. If no registered
+//encoder matches the Accept header, a problem with status 406 is sent.
+func (r *Response) Write(writer http.ResponseWriter, request *http.Request, code int, payload interface{}) {
+	if writer == nil {
+		r.logError("writer is nil")
+		return
+	}
+
+	writer.Header().Add(HeaderKeyVary, HeaderKeyAccept)
+
+	var accept string
+	if request != nil {
+		accept = request.Header.Get(HeaderKeyAccept)
+	}
+
+	encoders := r.getEncoders()
+
+	encoder, mediaType, ok := encoders.Negotiate(accept)
+	if !ok {
+		problem := NewProblem(
+			http.StatusNotAcceptable,
+			fmt.Sprintf("none of the accepted media types is supported: %s", accept),
+		)
+		problem.Extensions = map[string]interface{}{"supported_media_types": encoders.MediaTypes()}
+		r.WriteProblem(writer, request, problem)
+
+		return
+	}
+
+	response, err := encoder.Encode(payload)
+	if err != nil {
+		msg := errorJSON{Error: fmt.Sprintf("failed to encode response payload as %s: %v", mediaType, err)}
+		r.logError(msg.Error)
+		r.WriteJSON(writer, http.StatusInternalServerError, msg)
+
+		return
+	}
+
+	writer.Header().Set(HeaderKeyContentType, mediaType)
+	writer.WriteHeader(code)
+
+	if _, err := writer.Write(response); err != nil {
+		r.logError(fmt.Sprintf("failed to write response: %v", err))
+	}
+}
+
+//WriteRequestError sends a problem for an error returned by Request. A *RequestError is sent with its status and
+//field errors, any other error as internal server error.
+func (r *Response) WriteRequestError(writer http.ResponseWriter, request *http.Request, err error) {
+	var requestErr *RequestError
+	if errors.As(err, &requestErr) {
+		r.WriteProblem(writer, request, requestErr.Problem())
+		return
+	}
+
+	r.logError(fmt.Sprintf("failed to process request: %v", err))
+	r.WriteProblem(writer, request, NewProblem(http.StatusInternalServerError, ""))
+}
+
+func (r *Response) getEncoders() *Encoders {
+	if r == nil || r.Encoders == nil {
+		return DefaultEncoders()
+	}
+
+	return r.Encoders
+}
+
 type errorJSON struct {
 	Error string `json:"error"`
 }
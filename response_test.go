@@ -1,6 +1,7 @@
 package smis_test
 
 import (
+	"encoding/xml"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -152,3 +153,141 @@ func TestResponse_WriteJSON_NoWriter(t *testing.T) {
 		})
 	}
 }
+
+type payloadTest struct {
+	XMLName xml.Name `json:"-" xml:"user"`
+	Name    string   `json:"name" xml:"name"`
+}
+
+func TestResponse_Write(t *testing.T) { // nolint: funlen
+	testCases := []struct {
+		name                string
+		accept              string
+		payload             interface{}
+		expectedCode        int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "json",
+			payload:             payloadTest{Name: "test"},
+			expectedCode:        http.StatusCreated,
+			expectedContentType: smis.HeaderContentTypeJSON,
+			expectedBody:        `{"name":"test"}`,
+		},
+		{
+			name:                "xml",
+			accept:              "application/xml",
+			payload:             payloadTest{Name: "test"},
+			expectedCode:        http.StatusCreated,
+			expectedContentType: smis.HeaderContentTypeXML,
+			expectedBody:        xml.Header + `<user><name>test</name></user>`,
+		},
+		{
+			name:                "yaml",
+			accept:              "application/yaml",
+			payload:             payloadTest{Name: "test"},
+			expectedCode:        http.StatusCreated,
+			expectedContentType: smis.HeaderContentTypeYAML,
+			expectedBody:        "name: test\n",
+		},
+		{
+			name:                "msgpack",
+			accept:              "application/msgpack",
+			payload:             payloadTest{Name: "test"},
+			expectedCode:        http.StatusCreated,
+			expectedContentType: smis.HeaderContentTypeMessagePack,
+			expectedBody:        "\x81\xa4name\xa4test",
+		},
+		{
+			name:                "not acceptable",
+			accept:              "text/html",
+			payload:             payloadTest{Name: "test"},
+			expectedCode:        http.StatusNotAcceptable,
+			expectedContentType: smis.HeaderContentTypeProblemJSON,
+			expectedBody: `{"detail":"none of the accepted media types is supported: text/html","instance":"/",` +
+				`"status":406,"supported_media_types":["application/json","application/xml","application/yaml",` +
+				`"application/msgpack"],"title":"Not Acceptable","type":"about:blank"}`,
+		},
+		{
+			name:                "encoding fails",
+			accept:              "application/xml",
+			payload:             map[string]string{"name": "test"},
+			expectedCode:        http.StatusInternalServerError,
+			expectedContentType: smis.HeaderContentTypeJSON,
+			expectedBody: `{"error":"failed to encode response payload as application/xml: ` +
+				`xml: unsupported type: map[string]string"}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.Header.Set(smis.HeaderKeyAccept, testCase.accept)
+
+			w := httptest.NewRecorder()
+			response := &smis.Response{}
+			response.Write(w, request, http.StatusCreated, testCase.payload)
+
+			if testCase.expectedCode != w.Code {
+				t.Errorf("expected code %d but got %d", testCase.expectedCode, w.Code)
+			}
+
+			if contentType := w.Header().Get(smis.HeaderKeyContentType); testCase.expectedContentType != contentType {
+				t.Errorf("expected content type '%s' but got '%s'", testCase.expectedContentType, contentType)
+			}
+
+			if vary := w.Header().Get(smis.HeaderKeyVary); vary != smis.HeaderKeyAccept {
+				t.Errorf("expected vary header '%s' but got '%s'", smis.HeaderKeyAccept, vary)
+			}
+
+			if testCase.expectedBody != w.Body.String() {
+				t.Errorf("expected body '%s' but got '%s'", testCase.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestResponse_WriteRequestError(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          error
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "request error",
+			err: &smis.RequestError{
+				Status: http.StatusUnprocessableEntity,
+				Detail: "validation failed",
+				Fields: []smis.FieldError{{Field: "name", Rule: "required", Message: "is required"}},
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: `{"detail":"validation failed","errors":[{"field":"name","rule":"required",` +
+				`"message":"is required"}],"instance":"/","status":422,"title":"Unprocessable Entity",` +
+				`"type":"about:blank"}`,
+		},
+		{
+			name:         "other error",
+			err:          errors.New("invalid rule"),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"instance":"/","status":500,"title":"Internal Server Error","type":"about:blank"}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			response := &smis.Response{}
+			response.WriteRequestError(w, httptest.NewRequest(http.MethodPost, "/", nil), testCase.err)
+
+			if testCase.expectedCode != w.Code {
+				t.Errorf("expected code %d but got %d", testCase.expectedCode, w.Code)
+			}
+
+			if testCase.expectedBody != w.Body.String() {
+				t.Errorf("expected body '%s' but got '%s'", testCase.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package smis
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// RoutesPathDefault is the default path of the endpoint serving the route table
+const RoutesPathDefault = "/routes"
+
+// nolint: gochecknoglobals
+var funcSuffixRegex = regexp.MustCompile(`(\.func\d+)+$`)
+
+// Route describes a registered route. Middleware contains the names of the middleware executed for the route in
+// order, starting with the default chain.
+type Route struct {
+	Chain        string   `json:"chain"`
+	PathTemplate string   `json:"path_template"`
+	Methods      []string `json:"methods"`
+	Name         string   `json:"name,omitempty"`
+	Middleware   []string `json:"middleware"`
+	FileServer   bool     `json:"file_server"`
+}
+
+// Routes returns all routes having a handler in the order the router matches them. Chains are matched at the
+// position their sub router was created.
+func (s *Service) Routes() ([]Route, error) {
+	routes := make([]Route, 0)
+
+	err := s.Router.Walk(func(route *mux.Route, router *mux.Router, _ []*mux.Route) error {
+		if route.GetHandler() == nil {
+			return nil // the route of a sub router
+		}
+
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+
+		methods, err := route.GetMethods()
+		if err != nil {
+			methods = []string{}
+		}
+
+		chain := s.chainOfRouter(router)
+
+		middleware := append([]string{}, s.middlewareNames[MiddlewareChainDefault]...)
+		if chain != MiddlewareChainDefault {
+			middleware = append(middleware, s.middlewareNames[chain]...)
+		}
+
+		routes = append(routes, Route{
+			Chain:        chain,
+			PathTemplate: template,
+			Methods:      methods,
+			Name:         route.GetName(),
+			Middleware:   middleware,
+			FileServer:   s.fileServers[route],
+		})
+
+		return nil
+	})
+
+	return routes, err
+}
+
+// WithRoutesEndpoint registers an endpoint serving the route table as JSON at the given chain. As the table reveals
+// the internals of the service, you should use a restricted chain. If path is empty, RoutesPathDefault is used.
+func (s *Service) WithRoutesEndpoint(chain, path string) (*Service, error) {
+	if path == "" {
+		path = RoutesPathDefault
+	}
+
+	handler := func(writer http.ResponseWriter, request *http.Request) {
+		log := s.NewLogForRequestID(request.Context())
+		response := &Response{Log: log}
+
+		routes, err := s.Routes()
+		if err != nil {
+			log.Errorf("failed to collect routes: %v", err)
+			response.WriteProblem(writer, request, NewProblem(http.StatusInternalServerError, ""))
+
+			return
+		}
+
+		response.WriteJSON(writer, http.StatusOK, routes)
+	}
+
+	_, err := s.RegisterEndpointToChain(chain, path, http.MethodGet, handler,
+		WithSummary("Route table"), WithTags("meta"), WithResponse(http.StatusOK, "", []Route{}))
+
+	return s, err
+}
+
+// chainOfRouter returns the chain the router belongs to.
+func (s *Service) chainOfRouter(router *mux.Router) string {
+	for chain, subRouter := range s.SubRouters {
+		if subRouter == router {
+			return chain
+		}
+	}
+
+	return MiddlewareChainDefault
+}
+
+// chainOfRoute returns the chain owning the route. The owners are collected by walking the router and cached until
+// an unknown route is requested.
+func (s *Service) chainOfRoute(route *mux.Route) string {
+	s.routeChainsMutex.RLock()
+	chain, ok := s.routeChains[route]
+	s.routeChainsMutex.RUnlock()
+
+	if ok {
+		return chain
+	}
+
+	chains := make(map[*mux.Route]string)
+
+	_ = s.Router.Walk(func(r *mux.Route, router *mux.Router, _ []*mux.Route) error {
+		chains[r] = s.chainOfRouter(router)
+		return nil
+	})
+
+	s.routeChainsMutex.Lock()
+	s.routeChains = chains
+	s.routeChainsMutex.Unlock()
+
+	if chain, ok = chains[route]; ok {
+		return chain
+	}
+
+	return MiddlewareChainDefault
+}
+
+// middlewareName returns the name of the function implementing the middleware without the path of the package,
+// e.g. requestid.(*requestID).handler.
+func middlewareName(middleware mux.MiddlewareFunc) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(middleware).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+
+	name := fn.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	name = strings.TrimSuffix(name, "-fm")
+
+	return funcSuffixRegex.ReplaceAllString(name, "")
+}
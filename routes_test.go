@@ -0,0 +1,107 @@
+package smis_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis"
+	"github.com/rebel-l/smis/middleware/requestid"
+
+	"github.com/sirupsen/logrus"
+)
+
+func passThroughMiddlewareTest(next http.Handler) http.Handler {
+	return next
+}
+
+func TestService_Routes(t *testing.T) { // nolint: funlen
+	log := logrus.New()
+
+	service, err := smis.NewService(&http.Server{}, mux.NewRouter(), log)
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	handler := func(_ http.ResponseWriter, _ *http.Request) {}
+
+	service.AddMiddlewareForDefaultChain(requestid.New(log))
+	service.AddMiddlewareForRestrictedChain(passThroughMiddlewareTest)
+
+	route, err := service.RegisterEndpoint("/ping", http.MethodGet, handler)
+	if err != nil {
+		t.Fatalf("failed to register endpoint: %s", err)
+	}
+
+	route.Name("ping")
+
+	if _, err = service.RegisterEndpointToRestictedChain("/users/{id}", http.MethodDelete, handler); err != nil {
+		t.Fatalf("failed to register endpoint: %s", err)
+	}
+
+	if _, err = service.RegisterFileServer("/static/", http.MethodGet, "."); err != nil {
+		t.Fatalf("failed to register file server: %s", err)
+	}
+
+	if _, err = service.WithRoutesEndpoint(smis.MiddlewareChainRestricted, ""); err != nil {
+		t.Fatalf("failed to register routes endpoint: %s", err)
+	}
+
+	expected := []smis.Route{
+		{
+			Chain:        smis.MiddlewareChainRestricted,
+			PathTemplate: "/restricted/users/{id}",
+			Methods:      []string{http.MethodDelete},
+			Middleware:   []string{"requestid.(*requestID).handler", "smis_test.passThroughMiddlewareTest"},
+		},
+		{
+			Chain:        smis.MiddlewareChainRestricted,
+			PathTemplate: "/restricted/routes",
+			Methods:      []string{http.MethodGet},
+			Middleware:   []string{"requestid.(*requestID).handler", "smis_test.passThroughMiddlewareTest"},
+		},
+		{
+			Chain:        smis.MiddlewareChainDefault,
+			PathTemplate: "/ping",
+			Methods:      []string{http.MethodGet},
+			Name:         "ping",
+			Middleware:   []string{"requestid.(*requestID).handler"},
+		},
+		{
+			Chain:        smis.MiddlewareChainDefault,
+			PathTemplate: "/static/",
+			Methods:      []string{http.MethodGet},
+			Middleware:   []string{"requestid.(*requestID).handler"},
+			FileServer:   true,
+		},
+	}
+
+	got, err := service.Routes()
+	if err != nil {
+		t.Fatalf("expected no error but got: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected routes\n%+v\nbut got\n%+v", expected, got)
+	}
+
+	w := httptest.NewRecorder()
+	service.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/restricted/routes", nil))
+
+	if http.StatusOK != w.Code {
+		t.Fatalf("expected status %d but got %d", http.StatusOK, w.Code)
+	}
+
+	var served []smis.Route
+	if err := json.Unmarshal(w.Body.Bytes(), &served); err != nil {
+		t.Fatalf("failed to decode routes: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, served) {
+		t.Errorf("expected served routes\n%+v\nbut got\n%+v", expected, served)
+	}
+}
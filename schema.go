@@ -0,0 +1,312 @@
+package smis
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/rebel-l/smis/openapi"
+)
+
+// nolint: gochecknoglobals
+var (
+	typeJSONMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	typeProblem       = reflect.TypeOf(Problem{})
+)
+
+// schemaGenerator derives OpenAPI schemas from Go types. Named structs are added to the components of the document
+// and referenced.
+type schemaGenerator struct {
+	doc   *openapi.Document
+	names map[reflect.Type]string
+	types map[string]reflect.Type
+}
+
+func newSchemaGenerator(doc *openapi.Document) *schemaGenerator {
+	return &schemaGenerator{
+		doc:   doc,
+		names: make(map[reflect.Type]string),
+		types: make(map[string]reflect.Type),
+	}
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) *openapi.Schema { // nolint: gocyclo
+	t = indirect(t)
+
+	switch t {
+	case typeTime:
+		return &openapi.Schema{Type: "string", Format: "date-time"}
+	case typeDuration:
+		return &openapi.Schema{Type: "integer", Format: "int64", Description: "duration in nanoseconds"}
+	case typeProblem:
+		return openapi.NewRef(g.problemComponent())
+	}
+
+	if t.Implements(typeJSONMarshaler) || reflect.PtrTo(t).Implements(typeJSONMarshaler) {
+		return &openapi.Schema{}
+	}
+
+	if t.Implements(typeTextUnmarshaler) || reflect.PtrTo(t).Implements(typeTextUnmarshaler) {
+		return &openapi.Schema{Type: "string"}
+	}
+
+	switch t.Kind() { // nolint: exhaustive
+	case reflect.Bool:
+		return &openapi.Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &openapi.Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &openapi.Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &openapi.Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &openapi.Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &openapi.Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openapi.Schema{Type: "string", Format: "byte"}
+		}
+
+		return &openapi.Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &openapi.Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+
+		return openapi.NewRef(g.component(t))
+	default:
+		return &openapi.Schema{}
+	}
+}
+
+// component adds the named struct to the components and returns its name. If two types share a name, the package
+// name is prepended.
+func (g *schemaGenerator) component(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if other, ok := g.types[name]; ok && other != t {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+
+	g.names[t] = name
+	g.types[name] = t
+
+	schema := &openapi.Schema{}
+	g.doc.AddSchema(name, schema) // added before the fields to support recursive types
+	*schema = *g.structSchema(t)
+
+	return name
+}
+
+// problemComponent adds the schema of Problem, it can't be derived as Problem encodes itself.
+func (g *schemaGenerator) problemComponent() string {
+	name := typeProblem.Name()
+	if _, ok := g.names[typeProblem]; ok {
+		return name
+	}
+
+	g.names[typeProblem] = name
+	g.types[name] = typeProblem
+
+	g.doc.AddSchema(name, &openapi.Schema{
+		Type:        "object",
+		Description: "problem details as defined by RFC 7807",
+		Properties: map[string]*openapi.Schema{
+			"type":       {Type: "string"},
+			"title":      {Type: "string"},
+			"status":     {Type: "integer", Format: "int32"},
+			"detail":     {Type: "string"},
+			"instance":   {Type: "string"},
+			"request_id": {Type: "string"},
+		},
+		AdditionalProperties: &openapi.Schema{},
+	})
+
+	return name
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) *openapi.Schema {
+	schema := &openapi.Schema{Type: "object", Properties: make(map[string]*openapi.Schema)}
+	g.addProperties(schema, t)
+
+	return schema
+}
+
+func (g *schemaGenerator) addProperties(schema *openapi.Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			g.addProperties(schema, field.Type)
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		property := g.schema(field.Type)
+		if applyRules(property, field) {
+			schema.Required = append(schema.Required, name)
+		}
+
+		schema.Properties[name] = property
+	}
+}
+
+// parameters derives the parameters from the path, query and header tags of the struct, see Request.Bind().
+func (g *schemaGenerator) parameters(t reflect.Type) []openapi.Parameter {
+	t = indirect(t)
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var parameters []openapi.Parameter
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			parameters = append(parameters, g.parameters(field.Type)...)
+			continue
+		}
+
+		for _, source := range parameterSources {
+			name := field.Tag.Get(source.tag)
+			if name == "" {
+				continue
+			}
+
+			schema := g.schema(field.Type)
+			if indirect(field.Type) == typeDuration {
+				schema = &openapi.Schema{Type: "string", Format: "duration"} // parsed by time.ParseDuration()
+			}
+
+			required := applyRules(schema, field)
+
+			if def, ok := field.Tag.Lookup(TagDefault); ok {
+				schema.Default = defaultValue(schema.Type, def)
+			}
+
+			parameters = append(parameters, openapi.Parameter{
+				Name:     name,
+				In:       source.tag,
+				Required: required || source.tag == TagPath,
+				Schema:   schema,
+			})
+
+			break
+		}
+	}
+
+	return parameters
+}
+
+// applyRules adds the constraints of the validate tag to the schema and returns true if the field is required.
+func applyRules(schema *openapi.Schema, field reflect.StructField) bool {
+	tag, ok := field.Tag.Lookup(TagValidate)
+	if !ok {
+		return false
+	}
+
+	var required bool
+
+	for _, r := range splitRules(tag) {
+		switch r.name {
+		case RuleRequired:
+			required = true
+		case RuleMin, RuleMax:
+			applyRange(schema, r)
+		case RuleRegex:
+			schema.Pattern = r.param
+		case RuleEnum:
+			for _, v := range strings.Split(r.param, "|") {
+				schema.Enum = append(schema.Enum, defaultValue(schema.Type, v))
+			}
+		}
+	}
+
+	return required
+}
+
+func applyRange(schema *openapi.Schema, r rule) {
+	limit, err := strconv.ParseFloat(r.param, 64)
+	if err != nil {
+		return
+	}
+
+	if schema.Type == "integer" || schema.Type == "number" {
+		if r.name == RuleMin {
+			schema.Minimum = &limit
+		} else {
+			schema.Maximum = &limit
+		}
+
+		return
+	}
+
+	if limit < 0 {
+		return
+	}
+
+	length := uint64(limit)
+
+	switch schema.Type {
+	case "string":
+		if r.name == RuleMin {
+			schema.MinLength = &length
+		} else {
+			schema.MaxLength = &length
+		}
+	case "array":
+		if r.name == RuleMin {
+			schema.MinItems = &length
+		} else {
+			schema.MaxItems = &length
+		}
+	}
+}
+
+// defaultValue converts the raw value to the JSON type of the schema.
+func defaultValue(schemaType, raw string) interface{} {
+	switch schemaType {
+	case "integer":
+		if i, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(raw, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	}
+
+	return raw
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
@@ -0,0 +1,80 @@
+package smis
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rebel-l/smis/openapi"
+)
+
+type schemaNodeTest struct {
+	Name     string            `json:"name" validate:"required,min=1,max=20"`
+	Kind     string            `json:"kind,omitempty" validate:"enum=leaf|branch"`
+	Weight   float64           `json:"weight" validate:"min=0"`
+	Children []*schemaNodeTest `json:"children" validate:"max=10"`
+	Labels   map[string]int    `json:"labels"`
+	Created  time.Time         `json:"created"`
+	Data     []byte            `json:"data"`
+	Hidden   string            `json:"-"`
+	internal string
+}
+
+type schemaEmbeddedTest struct {
+	ID uint32 `json:"id"`
+}
+
+type schemaUserTest struct {
+	schemaEmbeddedTest
+	Email string          `json:"email" validate:"regex=^.+@.+$"`
+	Node  *schemaNodeTest `json:"node"`
+	Meta  struct {
+		Active bool `json:"active"`
+	} `json:"meta"`
+}
+
+func TestSchemaGenerator_Schema(t *testing.T) {
+	doc := openapi.NewDocument(openapi.Info{})
+	generator := newSchemaGenerator(doc)
+
+	got := generator.schema(reflect.TypeOf([]schemaUserTest{}))
+
+	expectedSchema := `{"type":"array","items":{"$ref":"#/components/schemas/schemaUserTest"}}`
+	if b, _ := json.Marshal(got); expectedSchema != string(b) {
+		t.Errorf("expected schema\n%s\nbut got\n%s", expectedSchema, b)
+	}
+
+	expectedComponents := `{"schemas":{"schemaNodeTest":{"type":"object","properties":{"children":{"type":"array",` +
+		`"items":{"$ref":"#/components/schemas/schemaNodeTest"},"maxItems":10},"created":{"type":"string",` +
+		`"format":"date-time"},"data":{"type":"string","format":"byte"},"kind":{"type":"string","enum":["leaf",` +
+		`"branch"]},"labels":{"type":"object","additionalProperties":{"type":"integer","format":"int64"}},` +
+		`"name":{"type":"string","minLength":1,"maxLength":20},"weight":{"type":"number","format":"double",` +
+		`"minimum":0}},"required":["name"]},"schemaUserTest":{"type":"object","properties":{"email":{"type":` +
+		`"string","pattern":"^.+@.+$"},"id":{"type":"integer","format":"int32"},"meta":{"type":"object",` +
+		`"properties":{"active":{"type":"boolean"}}},"node":{"$ref":"#/components/schemas/schemaNodeTest"}}}}}`
+	if b, _ := json.Marshal(doc.Components); expectedComponents != string(b) {
+		t.Errorf("expected components\n%s\nbut got\n%s", expectedComponents, b)
+	}
+}
+
+func TestSchemaGenerator_Parameters(t *testing.T) {
+	type params struct {
+		schemaEmbeddedTest
+		ID      string        `path:"id"`
+		Page    int           `query:"page" default:"1" validate:"min=1"`
+		Timeout time.Duration `query:"timeout" default:"5s"`
+		Tenant  string        `header:"X-Tenant" validate:"required"`
+		Ignored string
+	}
+
+	got := newSchemaGenerator(openapi.NewDocument(openapi.Info{})).parameters(reflect.TypeOf(&params{}))
+
+	expected := `[{"name":"id","in":"path","required":true,"schema":{"type":"string"}},{"name":"page",` +
+		`"in":"query","schema":{"type":"integer","format":"int64","default":1,"minimum":1}},{"name":"timeout",` +
+		`"in":"query","schema":{"type":"string","format":"duration","default":"5s"}},{"name":"X-Tenant",` +
+		`"in":"header","required":true,"schema":{"type":"string"}}]`
+	if b, _ := json.Marshal(got); expected != string(b) {
+		t.Errorf("expected parameters\n%s\nbut got\n%s", expected, b)
+	}
+}
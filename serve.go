@@ -0,0 +1,123 @@
+package smis
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// ListenerServer is a server able to accept connections on a given listener, e.g. *http.Server.
+type ListenerServer interface {
+	Serve(listener net.Listener) error
+}
+
+// TLSListenerServer is a server able to serve TLS on a given listener, e.g. *http.Server.
+type TLSListenerServer interface {
+	ServeTLS(listener net.Listener, certFile, keyFile string) error
+}
+
+// ServeOn makes the server accept connections on the given listeners instead of listening on its address, e.g. on
+// a Unix domain socket or sockets passed by systemd, see package socket. The server must implement ListenerServer.
+// The listeners are closed by the shutdown of the server.
+func (s *Service) ServeOn(listeners ...net.Listener) *Service {
+	s.netListeners = append(s.netListeners, listeners...)
+	return s
+}
+
+// listenAndServe starts the server on its address or on the listeners given by ServeOn(), with TLS if it is
+// configured.
+func (s *Service) listenAndServe() error {
+	if s.boundListener != nil {
+		listener := s.boundListener
+		s.boundListener = nil
+
+		return s.serveListeners([]net.Listener{listener})
+	}
+
+	if len(s.netListeners) > 0 {
+		return s.serveListeners(s.netListeners)
+	}
+
+	if s.TLSReloader == nil {
+		return s.Server.ListenAndServe()
+	}
+
+	server, ok := s.Server.(TLSServer)
+	if !ok {
+		return fmt.Errorf("server %T doesn't support TLS", s.Server)
+	}
+
+	// the certificate is provided by tls.Config.GetCertificate
+	return server.ListenAndServeTLS("", "")
+}
+
+// serveListeners serves on all listeners and returns the first error. The other listeners keep serving until the
+// server is shut down.
+func (s *Service) serveListeners(listeners []net.Listener) error {
+	serve, err := s.getServeFunc()
+	if err != nil {
+		return err
+	}
+
+	errs := make(chan error, len(listeners))
+
+	for _, listener := range listeners {
+		s.Log.Infof("Serving on %s %s", listener.Addr().Network(), listener.Addr())
+
+		go func(listener net.Listener) {
+			errs <- serve(listener)
+		}(listener)
+	}
+
+	return <-errs
+}
+
+func (s *Service) getServeFunc() (func(listener net.Listener) error, error) {
+	if s.TLSReloader == nil {
+		server, ok := s.Server.(ListenerServer)
+		if !ok {
+			return nil, fmt.Errorf("server %T can't serve on listeners", s.Server)
+		}
+
+		return server.Serve, nil
+	}
+
+	server, ok := s.Server.(TLSListenerServer)
+	if !ok {
+		return nil, fmt.Errorf("server %T can't serve TLS on listeners", s.Server)
+	}
+
+	return func(listener net.Listener) error {
+		return server.ServeTLS(listener, "", "")
+	}, nil
+}
+
+// bind validates the routes and opens the listener of an *http.Server serving on its address, so the server listens
+// as soon as bind returns. Other servers can't be bound in advance, they start listening in ListenAndServe().
+func (s *Service) bind() error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	server, ok := s.Server.(*http.Server)
+	if !ok || len(s.netListeners) > 0 {
+		return nil
+	}
+
+	addr := server.Addr
+	if addr == "" {
+		addr = ":http"
+		if s.TLSReloader != nil {
+			addr = ":https"
+		}
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	s.boundListener = listener
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package smis_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis"
+	"github.com/rebel-l/smis/socket"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestService_ServeOn(t *testing.T) { // nolint: funlen
+	dir, err := ioutil.TempDir("", "smis")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	path := filepath.Join(dir, "smis.sock")
+
+	listener, err := socket.Unix(path, 0600)
+	if err != nil {
+		t.Fatalf("failed to create socket: %s", err)
+	}
+
+	router := mux.NewRouter()
+
+	service, err := smis.NewService(&http.Server{Handler: router}, router, logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	_, err = service.RegisterEndpoint("/ping", http.MethodGet, func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusNoContent)
+	})
+	if err != nil {
+		t.Fatalf("failed to register endpoint: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() {
+		done <- service.ServeOn(listener).Run(ctx)
+	}()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", path)
+			},
+		},
+		Timeout: time.Second,
+	}
+
+	response, err := client.Get("http://unix/ping")
+	if err != nil {
+		cancel()
+		t.Fatalf("failed to request service on socket: %s", err)
+	}
+
+	_ = response.Body.Close()
+
+	if http.StatusNoContent != response.StatusCode {
+		t.Errorf("expected status %d but got %d", http.StatusNoContent, response.StatusCode)
+	}
+
+	cancel()
+
+	if err = <-done; err != nil {
+		t.Errorf("expected no error but got: %s", err)
+	}
+
+	if _, err = os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected socket to be removed after shutdown but got: %v", err)
+	}
+}
+
+func TestService_ServeOn_Error(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	defer func() {
+		_ = listener.Close()
+	}()
+
+	service, err := smis.NewService(serverNoTLSTest{}, mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	if err = service.ServeOn(listener).ListenAndServe(); err == nil {
+		t.Error("expected an error for server not supporting listeners but got nil")
+	}
+}
@@ -3,16 +3,28 @@ package smis
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
 
 	"github.com/rebel-l/go-utils/slice"
+	"github.com/rebel-l/smis/health"
+	"github.com/rebel-l/smis/metrics"
 	"github.com/rebel-l/smis/middleware"
 	"github.com/rebel-l/smis/middleware/cors"
+	"github.com/rebel-l/smis/middleware/recovery"
 	"github.com/rebel-l/smis/middleware/requestid"
+	"github.com/rebel-l/smis/middleware/tracing"
+	"github.com/rebel-l/smis/tlsconfig"
 
 	"github.com/sirupsen/logrus"
 )
@@ -26,19 +38,69 @@ const (
 
 	// MiddlewareChainRestricted is the identifier for the restricted middleware chain
 	MiddlewareChainRestricted = "restricted"
+
+	// ShutdownTimeoutDefault is the default time in-flight requests have to finish on shutdown
+	ShutdownTimeoutDefault = 30 * time.Second
 )
 
+// nolint: gochecknoglobals
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
 // Server is an interface to describe how to serve endpoints.
 type Server interface {
 	ListenAndServe() error
 }
 
+// ShutdownServer is a server able to shut down gracefully, e.g. *http.Server. It is required by Run().
+type ShutdownServer interface {
+	Shutdown(ctx context.Context) error
+}
+
 // Service represents the fields necessary for a service.
 type Service struct {
-	Log        logrus.FieldLogger
-	Router     *mux.Router
-	Server     Server
-	SubRouters map[string]*mux.Router
+	Log             logrus.FieldLogger
+	Router          *mux.Router
+	Server          Server
+	SubRouters      map[string]*mux.Router
+	ShutdownTimeout time.Duration
+
+	// DrainDelay is the time readiness fails before the server is shut down.
+	DrainDelay time.Duration
+
+	// Health is nil until the health endpoints are initialized, see WithHealthEndpoints().
+	Health *health.Registry
+
+	// Metrics is nil until the metrics are initialized, see WithMetrics().
+	Metrics *metrics.Registry
+
+	// TLSReloader reloads the certificate, it is nil until TLS is initialized, see WithTLS().
+	TLSReloader *tlsconfig.Reloader
+
+	// UseProblemJSON makes the not found and method not allowed handlers respond problem JSON (RFC 7807) instead of
+	// plain text.
+	UseProblemJSON bool
+
+	// ErrorMapper maps errors returned by a HandlerFuncE to problems, if nil only *RequestError is mapped and
+	// everything else is a 500.
+	ErrorMapper *ErrorMapper
+
+	// RequestID configures the requestid middleware added by WithDefaultMiddleware() and
+	// WithDefaultMiddlewareForPRChain(), e.g. to trust the request ID of a gateway.
+	RequestID requestid.Config
+
+	hooks                map[HookStage][]Hook
+	endpoints            map[*mux.Route]*Endpoint
+	fileServers          map[*mux.Route]bool
+	middlewareNames      map[string][]string
+	corsPolicies         map[string]mux.MiddlewareFunc
+	routeChains          map[*mux.Route]string
+	routeChainsMutex     sync.RWMutex
+	security             map[string][]chainSecurity
+	listeners            []*Service
+	listenerName         string
+	netListeners         []net.Listener
+	boundListener        net.Listener
+	errorHandlersWrapped bool
 }
 
 // NewService returns an initialized service struct.
@@ -92,6 +154,12 @@ func (s *Service) GetRouterForMiddlewareChain(chain string) *mux.Router {
 func (s *Service) AddMiddleware(chain string, middleware mux.MiddlewareFunc) {
 	router := s.GetRouterForMiddlewareChain(chain)
 	router.Use(middleware)
+
+	if s.middlewareNames == nil {
+		s.middlewareNames = make(map[string][]string)
+	}
+
+	s.middlewareNames[chain] = append(s.middlewareNames[chain], middlewareName(middleware))
 }
 
 // AddMiddlewareForDefaultChain adds middleware to the default chain. NOTE: The default chain is working without
@@ -112,48 +180,82 @@ func (s *Service) AddMiddlewareForRestrictedChain(middleware mux.MiddlewareFunc)
 
 // RegisterEndpoint registers a handler at the router for the given method and path.
 // In case the method is not known an error is returned, otherwise a *Route.
-func (s *Service) RegisterEndpoint(path, method string, f http.HandlerFunc) (*mux.Route, error) {
-	return s.RegisterEndpointToChain(MiddlewareChainDefault, path, method, f)
+func (s *Service) RegisterEndpoint(
+	path, method string, f http.HandlerFunc, opts ...EndpointOption,
+) (*mux.Route, error) {
+	return s.RegisterEndpointToChain(MiddlewareChainDefault, path, method, f, opts...)
 }
 
 // RegisterEndpointToPublicChain registers a handler at the router for the given method and path at the public chain.
 // In case the method is not known an error is returned, otherwise a *Route.
-func (s *Service) RegisterEndpointToPublicChain(path, method string, f http.HandlerFunc) (*mux.Route, error) {
-	return s.RegisterEndpointToChain(MiddlewareChainPublic, path, method, f)
+func (s *Service) RegisterEndpointToPublicChain(
+	path, method string, f http.HandlerFunc, opts ...EndpointOption,
+) (*mux.Route, error) {
+	return s.RegisterEndpointToChain(MiddlewareChainPublic, path, method, f, opts...)
 }
 
 // RegisterEndpointToRestictedChain registers a handler at the router for the given method and path at the
 // restricted chain.
 // In case the method is not known an error is returned, otherwise a *Route.
-func (s *Service) RegisterEndpointToRestictedChain(path, method string, f http.HandlerFunc) (*mux.Route, error) {
-	return s.RegisterEndpointToChain(MiddlewareChainRestricted, path, method, f)
+func (s *Service) RegisterEndpointToRestictedChain(
+	path, method string, f http.HandlerFunc, opts ...EndpointOption,
+) (*mux.Route, error) {
+	return s.RegisterEndpointToChain(MiddlewareChainRestricted, path, method, f, opts...)
 }
 
 // RegisterEndpointToChain registers a handler at the router for the given method and path at any chain. You can use
-// your custom chains with this method.
+// your custom chains with this method. The options describe the endpoint for the OpenAPI document, see OpenAPI().
 // In case the method is not known an error is returned, otherwise a *Route.
-func (s *Service) RegisterEndpointToChain(chain, path, method string, f http.HandlerFunc) (*mux.Route, error) {
+func (s *Service) RegisterEndpointToChain(
+	chain, path, method string, f http.HandlerFunc, opts ...EndpointOption,
+) (*mux.Route, error) {
 	methods := getAllowedHTTPMethods()
 	if methods.IsNotIn(method) {
 		return nil, fmt.Errorf("method %s is not allowed", method)
 	}
 
 	router := s.GetRouterForMiddlewareChain(chain)
+	route := router.HandleFunc(path, f).Methods(method)
+
+	if len(opts) > 0 {
+		endpoint := &Endpoint{}
+		for _, opt := range opts {
+			opt(endpoint)
+		}
+
+		if s.endpoints == nil {
+			s.endpoints = make(map[*mux.Route]*Endpoint)
+		}
+
+		s.endpoints[route] = endpoint
+	}
 
-	return router.HandleFunc(path, f).Methods(method), nil
+	return route, nil
 }
 
 // RegisterFileServer registers a file server to provide static files.
 func (s *Service) RegisterFileServer(path, method, filepath string) (*mux.Route, error) {
-	return s.Router.
-			PathPrefix(path).
-			Handler(http.StripPrefix(path, http.FileServer(http.Dir(filepath)))).
-			Methods(method),
-		nil
+	route := s.Router.
+		PathPrefix(path).
+		Handler(http.StripPrefix(path, http.FileServer(http.Dir(filepath)))).
+		Methods(method)
+
+	if s.fileServers == nil {
+		s.fileServers = make(map[*mux.Route]bool)
+	}
+
+	s.fileServers[route] = true
+
+	return route, nil
 }
 
-// ListenAndServe registers the catch all route and starts the server.
+// ListenAndServe registers the catch all route and starts the server. It fails if the routes conflict, see Validate().
 func (s *Service) ListenAndServe() error {
+	if err := s.Validate(); err != nil {
+		s.closeBoundListener()
+		return err
+	}
+
 	err := s.Router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
 		pathTemplate, err := route.GetPathTemplate()
 		if err != nil {
@@ -164,55 +266,193 @@ func (s *Service) ListenAndServe() error {
 	})
 
 	if err != nil {
+		s.closeBoundListener()
 		return err
 	}
 
-	return s.Server.ListenAndServe()
+	return s.listenAndServe()
 }
 
-// WithDefaultMiddleware initializes the recommended middleware for the default middleware chain.
-func (s *Service) WithDefaultMiddleware(config cors.Config) *Service {
-	mw := s.GetDefaultMiddleware(config)
+// Run starts the server and blocks until the context is canceled, the process receives SIGINT / SIGTERM or the
+// server stops by itself. On cancellation or signal the server is shut down gracefully and in-flight requests have
+// ShutdownTimeout to finish. A server closed by the shutdown is not treated as an error.
+// Lifecycle hooks are executed around the start and the shutdown of the server, see AddHook(). An *http.Server is
+// bound to its address before the after listen hooks run, if binding fails, no server is started. If the start fails,
+// the after shutdown hooks are executed to release the resources of the before listen hooks which succeeded.
+// Listeners are started together with the server, if one of them stops, all of them are shut down, see AddListener().
+// The servers of the service and the listeners need to implement ShutdownServer.
+func (s *Service) Run(ctx context.Context) error {
+	if err := s.checkShutdown(); err != nil {
+		return err
+	}
+
+	if err := s.runHooks(ctx, HookStageBeforeListen); err != nil {
+		return s.abortStart(err)
+	}
+
+	if err := s.bindServers(); err != nil {
+		return s.abortStart(err)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, shutdownSignals...)
+
+	defer signal.Stop(signals)
+
+	servers := s.startServers()
+
+	err := s.runHooks(ctx, HookStageAfterListen)
+	if err == nil {
+		select {
+		case server := <-servers.done:
+			server.stopped = true
+			err = server.wrap(server.err)
+
+			if server.name != "" {
+				s.Log.Infof("listener %s stopped, shutting down", server.name)
+			}
+		case sig := <-signals:
+			s.Log.Infof("received signal %s, shutting down", sig)
+		case <-ctx.Done():
+			s.Log.Info("context done, shutting down")
+		}
+	}
+
+	return s.shutdown(servers, err)
+}
+
+// abortStart executes the after shutdown hooks, so the resources acquired by the before listen hooks which succeeded
+// are released. The cause is the error which aborted the start.
+func (s *Service) abortStart(cause error) error {
+	result := Errors{}.Append(cause)
+	result = result.Append(s.runHooks(context.Background(), HookStageAfterShutdown))
+
+	return result.ErrorOrNil()
+}
+
+// shutdown executes the teardown hooks and shuts the servers down which are not stopped already. The cause is the
+// error which led to the shutdown, if any.
+func (s *Service) shutdown(servers *serverGroup, cause error) error {
+	s.startDraining()
+
+	result := Errors{}.Append(cause)
+	result = result.Append(s.runHooks(context.Background(), HookStageBeforeShutdown))
+	result = append(result, servers.shutdown()...)
+	result = result.Append(s.runHooks(context.Background(), HookStageAfterShutdown))
+
+	return result.ErrorOrNil()
+}
+
+// checkShutdown returns an error if the server or the server of a listener can't be shut down gracefully.
+func (s *Service) checkShutdown() error {
+	if _, ok := s.Server.(ShutdownServer); !ok {
+		return fmt.Errorf("server %T doesn't support graceful shutdown, it needs to implement ShutdownServer", s.Server)
+	}
+
+	for _, listener := range s.listeners {
+		if err := listener.checkShutdown(); err != nil {
+			return fmt.Errorf("listener %s: %w", listener.listenerName, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) getShutdownTimeout() time.Duration {
+	if s.ShutdownTimeout <= 0 {
+		return ShutdownTimeoutDefault
+	}
 
-	_ = mw.Walk(func(middleware mux.MiddlewareFunc) error {
+	return s.ShutdownTimeout
+}
+
+func filterServerClosed(err error) error {
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+// WithDefaultMiddleware initializes the recommended middleware for the default middleware chain. The CORS config is
+// the policy of the default chain, see WithCORS().
+func (s *Service) WithDefaultMiddleware(config cors.Config) *Service {
+	_ = s.getBaseMiddleware().Walk(func(middleware mux.MiddlewareFunc) error {
 		s.AddMiddlewareForDefaultChain(middleware)
 		return nil
 	})
 
+	s.setCORSPolicy(MiddlewareChainDefault, config)
+	s.withErrorHandlersMiddleware()
+
 	return s
 }
 
 // WithDefaultMiddlewareForPRChain initializes the recommended middleware for the public & restricted middleware chain.
+// The CORS config is the policy of both chains, use WithCORS() afterwards to give them different policies.
 func (s *Service) WithDefaultMiddlewareForPRChain(config cors.Config) *Service {
-	mw := s.GetDefaultMiddleware(config)
-
-	_ = mw.Walk(func(middleware mux.MiddlewareFunc) error {
+	_ = s.getBaseMiddleware().Walk(func(middleware mux.MiddlewareFunc) error {
 		s.AddMiddlewareForPublicChain(middleware)
 		s.AddMiddlewareForRestrictedChain(middleware)
 		return nil
 	})
 
+	s.setCORSPolicy(MiddlewareChainPublic, config)
+	s.setCORSPolicy(MiddlewareChainRestricted, config)
+	s.withErrorHandlersMiddleware()
+
 	return s
 }
 
-// GetDefaultMiddleware returns the default middleware every chain should have.
+// withErrorHandlersMiddleware adds the request ID to the not found and method not allowed handlers, as mux doesn't
+// execute the middleware of any chain if no route matches. It is done only once.
+func (s *Service) withErrorHandlersMiddleware() {
+	if s.errorHandlersWrapped {
+		return
+	}
+
+	mw := requestid.NewWithConfig(s.Log, s.RequestID)
+	s.Router.NotFoundHandler = mw(s.Router.NotFoundHandler)
+	s.Router.MethodNotAllowedHandler = mw(s.Router.MethodNotAllowedHandler)
+	s.errorHandlersWrapped = true
+}
+
+// GetDefaultMiddleware returns the default middleware every chain should have. The recovery middleware is the
+// outermost one to catch panics of all others.
 func (s *Service) GetDefaultMiddleware(config cors.Config) middleware.Slice {
+	mw := s.getBaseMiddleware()
+	mw = append(mw, cors.NewWithLog(s.Log, s.Router, config))
+
+	return mw
+}
+
+// getBaseMiddleware returns the default middleware without CORS.
+func (s *Service) getBaseMiddleware() middleware.Slice {
 	var mw middleware.Slice
 
-	mw = append(mw, requestid.New(s.Log))
-	mw = append(mw, cors.New(s.Router, config))
+	mw = append(mw, recovery.New(s.Log))
+	mw = append(mw, requestid.NewWithConfig(s.Log, s.RequestID))
 
 	return mw
 }
 
 // NewLogForRequestID returns a new logger with field request ID for better debugging / tracing request. This works
 // only if requestid middleware generated a request id before, otherwise the field for request ID would be empty.
+// If the tracing middleware started a span, trace and span ID are added as fields too.
 func (s *Service) NewLogForRequestID(ctx context.Context) logrus.FieldLogger {
-	return requestid.NewLoggerFromContext(ctx, s.Log)
+	return tracing.NewLoggerFromContext(ctx, requestid.NewLoggerFromContext(ctx, s.Log))
 }
 
 func (s *Service) notFoundHandler(writer http.ResponseWriter, request *http.Request) {
 	s.Log.Warnf("endpoint not implemented: %s | %s", request.Method, request.RequestURI)
+
+	if s.UseProblemJSON {
+		response := &Response{Log: s.Log}
+		response.WriteProblem(writer, request, NewProblem(http.StatusNotFound, "endpoint not implemented"))
+
+		return
+	}
+
 	writer.WriteHeader(http.StatusNotFound)
 
 	_, err := writer.Write([]byte("endpoint not implemented"))
@@ -222,8 +462,38 @@ func (s *Service) notFoundHandler(writer http.ResponseWriter, request *http.Requ
 }
 
 func (s *Service) methodNotAllowedHandler(writer http.ResponseWriter, request *http.Request) {
+	if s.servePreflight(writer, request) {
+		return
+	}
+
 	s.Log.Warnf("method not allowed: %s | %s", request.Method, request.RequestURI)
 
+	methods := s.getMethodsForPath(request)
+	writer.Header().Add("Allow", strings.Join(methods, ","))
+
+	if s.UseProblemJSON {
+		problem := NewProblem(
+			http.StatusMethodNotAllowed,
+			fmt.Sprintf("method %s not allowed, please check allowed methods", request.Method),
+		)
+		problem.Extensions = map[string]interface{}{"allowed_methods": methods}
+
+		response := &Response{Log: s.Log}
+		response.WriteProblem(writer, request, problem)
+
+		return
+	}
+
+	writer.WriteHeader(http.StatusMethodNotAllowed)
+
+	_, err := writer.Write([]byte("method not allowed, please check response headers for allowed methods"))
+	if err != nil {
+		s.Log.Errorf("notAllowedHandler failed to send response: %s", err)
+	}
+}
+
+// getMethodsForPath returns all methods except the one of the request, which have a route matching the path.
+func (s *Service) getMethodsForPath(request *http.Request) []string {
 	methods := make([]string, 0)
 
 	for _, m := range getAllowedHTTPMethods() {
@@ -241,13 +511,7 @@ func (s *Service) methodNotAllowedHandler(writer http.ResponseWriter, request *h
 		methods = append(methods, m)
 	}
 
-	writer.Header().Add("Allow", strings.Join(methods, ","))
-	writer.WriteHeader(http.StatusMethodNotAllowed)
-
-	_, err := writer.Write([]byte("method not allowed, please check response headers for allowed methods"))
-	if err != nil {
-		s.Log.Errorf("notAllowedHandler failed to send response: %s", err)
-	}
+	return methods
 }
 
 func getAllowedHTTPMethods() slice.StringSlice {
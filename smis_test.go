@@ -1,16 +1,18 @@
 //go:generate mockgen -destination ./tests/mocks/http_mock/responseWriter.go -package http_mock net/http ResponseWriter
 //go:generate mockgen -destination ./tests/mocks/logrus_mock/fieldlogger.go -package logrus_mock github.com/sirupsen/logrus FieldLogger
-//go:generate mockgen -destination ./tests/mocks/smis_mock/smis.go -package smis_mock github.com/rebel-l/smis Server
+//go:generate mockgen -destination ./tests/mocks/smis_mock/smis.go -package smis_mock github.com/rebel-l/smis Server,ShutdownServer
 
 package smis
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -26,6 +28,7 @@ import (
 	"github.com/rebel-l/smis/tests/mocks/smis_mock"
 
 	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
 )
 
 func TestNewService(t *testing.T) {
@@ -845,3 +848,283 @@ func TestService_WithDefaultMiddlewareForPRChain(t *testing.T) { // nolint: funl
 		})
 	}
 }
+
+func TestService_Run(t *testing.T) { // nolint: funlen
+	testcases := []struct {
+		name        string
+		serveErr    error
+		shutdownErr error
+		cancel      bool
+		noShutdown  bool
+		err         error
+	}{
+		{
+			name:   "context canceled",
+			cancel: true,
+		},
+		{
+			name:     "server fails",
+			serveErr: fmt.Errorf("address already in use"),
+			err:      fmt.Errorf("address already in use"),
+		},
+		{
+			name:        "shutdown fails",
+			cancel:      true,
+			shutdownErr: context.DeadlineExceeded,
+			err:         fmt.Errorf("failed to shutdown server gracefully: context deadline exceeded"),
+		},
+		{
+			name:       "shutdown not supported",
+			noShutdown: true,
+			err: fmt.Errorf(
+				"server *smis_mock.MockServer doesn't support graceful shutdown, it needs to implement ShutdownServer"),
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			closed := make(chan struct{})
+
+			serverMock := smis_mock.NewMockServer(ctrl)
+			shutdownMock := smis_mock.NewMockShutdownServer(ctrl)
+
+			switch {
+			case testcase.noShutdown:
+				serverMock.EXPECT().ListenAndServe().Times(0)
+			case testcase.cancel:
+				serverMock.EXPECT().ListenAndServe().MaxTimes(1).DoAndReturn(func() error {
+					<-closed
+					return http.ErrServerClosed
+				})
+				shutdownMock.EXPECT().Shutdown(gomock.Any()).Times(1).DoAndReturn(func(_ context.Context) error {
+					close(closed)
+					return testcase.shutdownErr
+				})
+			default:
+				serverMock.EXPECT().ListenAndServe().Times(1).Return(testcase.serveErr)
+				shutdownMock.EXPECT().Shutdown(gomock.Any()).Times(0)
+			}
+
+			var server Server = struct {
+				*smis_mock.MockServer
+				*smis_mock.MockShutdownServer
+			}{serverMock, shutdownMock}
+
+			if testcase.noShutdown {
+				server = serverMock
+			}
+
+			service, err := NewService(server, mux.NewRouter(), logrus.New())
+			if err != nil {
+				t.Fatalf("failed to create service: %s", err)
+			}
+
+			service.ShutdownTimeout = time.Second
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if testcase.cancel {
+				cancel()
+			} else {
+				defer cancel()
+			}
+
+			err = service.Run(ctx)
+			if testcase.err == nil && err != nil {
+				t.Errorf("Run should NOT throw an error, but got: %s", err)
+			}
+
+			if testcase.err != nil && (err == nil || testcase.err.Error() != err.Error()) {
+				t.Errorf("Run should throw an error '%s', but got '%v'", testcase.err, err)
+			}
+		})
+	}
+}
+
+func TestService_WithDefaultMiddleware_Panic(t *testing.T) {
+	log, hook := test.NewNullLogger()
+
+	service, err := NewService(&http.Server{}, mux.NewRouter(), log)
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	_, err = service.WithDefaultMiddleware(cors.Config{}).
+		RegisterEndpoint("/panic", http.MethodGet, func(_ http.ResponseWriter, _ *http.Request) {
+			panic("something went wrong")
+		})
+	if err != nil {
+		t.Fatalf("failed to register endpoint: %s", err)
+	}
+
+	w := httptest.NewRecorder()
+	service.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected code %d but got %d", http.StatusInternalServerError, w.Code)
+	}
+
+	expectedBody := `{"error":"internal server error"}`
+	if expectedBody != w.Body.String() {
+		t.Errorf("expected body '%s' but got '%s'", expectedBody, w.Body.String())
+	}
+
+	expectedID := w.Header().Get(requestid.HeaderRID)
+	if expectedID == "" {
+		t.Fatal("request ID in header should not be empty")
+	}
+
+	entry := hook.LastEntry()
+	if entry == nil || entry.Level != logrus.ErrorLevel {
+		t.Fatalf("expected the panic to be logged but got %v", entry)
+	}
+
+	if gotID := entry.Data[string(requestid.ContextKeyRequestID)]; expectedID != gotID {
+		t.Errorf("expected request ID '%s' in panic log but got '%v'", expectedID, gotID)
+	}
+}
+
+func TestService_WithDefaultMiddleware_ErrorHandlers(t *testing.T) {
+	service, err := NewService(&http.Server{}, mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	service.WithDefaultMiddleware(cors.Config{}).WithDefaultMiddlewareForPRChain(cors.Config{})
+
+	_, err = service.RegisterEndpoint("/user", http.MethodGet, func(_ http.ResponseWriter, _ *http.Request) {})
+	if err != nil {
+		t.Fatalf("failed to register endpoint: %s", err)
+	}
+
+	testcases := []struct {
+		name           string
+		request        *http.Request
+		expectedStatus int
+	}{
+		{
+			name:           "not found",
+			request:        httptest.NewRequest(http.MethodGet, "/unknown", nil),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "method not allowed",
+			request:        httptest.NewRequest(http.MethodPost, "/user", nil),
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			service.Router.ServeHTTP(w, testcase.request)
+			resp := w.Result()
+
+			if err := resp.Body.Close(); err != nil {
+				t.Fatalf("failed to close body: %s", err)
+			}
+
+			if testcase.expectedStatus != resp.StatusCode {
+				t.Errorf("expected status %d but got %d", testcase.expectedStatus, resp.StatusCode)
+			}
+
+			if resp.Header.Get(requestid.HeaderRID) == "" {
+				t.Error("request ID in header should not be empty")
+			}
+		})
+	}
+}
+
+func TestService_UseProblemJSON(t *testing.T) {
+	service, err := NewService(&http.Server{}, mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	service.UseProblemJSON = true
+
+	_, err = service.RegisterEndpoint("/user", http.MethodGet, func(_ http.ResponseWriter, _ *http.Request) {})
+	if err != nil {
+		t.Fatalf("failed to register endpoint: %s", err)
+	}
+
+	testcases := []struct {
+		name           string
+		request        *http.Request
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "not found",
+			request:        httptest.NewRequest(http.MethodGet, "/unknown", nil),
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{"detail":"endpoint not implemented","instance":"/unknown","status":404,` +
+				`"title":"Not Found","type":"about:blank"}`,
+		},
+		{
+			name:           "method not allowed",
+			request:        httptest.NewRequest(http.MethodPost, "/user", nil),
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody: `{"allowed_methods":["GET"],"detail":"method POST not allowed, please check allowed ` +
+				`methods","instance":"/user","status":405,"title":"Method Not Allowed","type":"about:blank"}`,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			service.Router.ServeHTTP(w, testcase.request)
+
+			if testcase.expectedStatus != w.Code {
+				t.Errorf("expected status %d but got %d", testcase.expectedStatus, w.Code)
+			}
+
+			if contentType := w.Header().Get(HeaderKeyContentType); contentType != HeaderContentTypeProblemJSON {
+				t.Errorf("expected content type '%s' but got '%s'", HeaderContentTypeProblemJSON, contentType)
+			}
+
+			if testcase.expectedBody != w.Body.String() {
+				t.Errorf("expected body '%s' but got '%s'", testcase.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestService_RequestID(t *testing.T) {
+	service, err := NewService(&http.Server{}, mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	service.RequestID = requestid.Config{TrustIncoming: true}
+	service.WithDefaultMiddleware(cors.Config{})
+
+	_, err = service.RegisterEndpoint("/user", http.MethodGet, func(_ http.ResponseWriter, _ *http.Request) {})
+	if err != nil {
+		t.Fatalf("failed to register endpoint: %s", err)
+	}
+
+	testcases := []struct {
+		name    string
+		request *http.Request
+	}{
+		{name: "route", request: httptest.NewRequest(http.MethodGet, "/user", nil)},
+		{name: "not found", request: httptest.NewRequest(http.MethodGet, "/unknown", nil)},
+		{name: "method not allowed", request: httptest.NewRequest(http.MethodPost, "/user", nil)},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			testcase.request.Header.Set(requestid.HeaderRID, "gateway-1")
+
+			w := httptest.NewRecorder()
+			service.Router.ServeHTTP(w, testcase.request)
+
+			if got := w.Header().Get(requestid.HeaderRID); got != "gateway-1" {
+				t.Errorf("expected incoming request ID 'gateway-1' but got '%s'", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,2 @@
+// Package socket provides listeners on Unix domain sockets and listeners passed by systemd socket activation.
+package socket
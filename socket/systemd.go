@@ -0,0 +1,104 @@
+package socket
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// EnvListenPID is the environment variable containing the PID of the process the sockets are passed to
+	EnvListenPID = "LISTEN_PID"
+
+	// EnvListenFDs is the environment variable containing the number of passed sockets
+	EnvListenFDs = "LISTEN_FDS"
+
+	// EnvListenFDNames is the environment variable containing the names of the sockets separated by colon
+	EnvListenFDNames = "LISTEN_FDNAMES"
+
+	// listenFDsStart is the first file descriptor passed by systemd
+	listenFDsStart = 3
+)
+
+// ErrNoSystemdSockets is returned if the process wasn't started by systemd socket activation
+var ErrNoSystemdSockets = errors.New("no sockets passed by systemd")
+
+// Systemd returns the listeners passed by systemd socket activation in the order of the socket unit. The environment
+// variables are unset afterwards, so child processes don't inherit the sockets.
+func Systemd() ([]net.Listener, error) {
+	named, err := SystemdWithNames()
+	if err != nil {
+		return nil, err
+	}
+
+	listeners := make([]net.Listener, len(named))
+	for i, n := range named {
+		listeners[i] = n.Listener
+	}
+
+	return listeners, nil
+}
+
+// NamedListener is a listener passed by systemd with the name set by FileDescriptorName= in the socket unit.
+type NamedListener struct {
+	Name     string
+	Listener net.Listener
+}
+
+// SystemdWithNames returns the listeners passed by systemd socket activation with their names, see Systemd().
+func SystemdWithNames() ([]NamedListener, error) {
+	defer func() {
+		_ = os.Unsetenv(EnvListenPID)
+		_ = os.Unsetenv(EnvListenFDs)
+		_ = os.Unsetenv(EnvListenFDNames)
+	}()
+
+	return systemdListeners(
+		os.Getenv(EnvListenPID), os.Getenv(EnvListenFDs), os.Getenv(EnvListenFDNames), os.Getpid(), listenFDsStart,
+	)
+}
+
+func systemdListeners(pid, fds, names string, ownPID, start int) ([]NamedListener, error) {
+	if pid == "" || fds == "" {
+		return nil, ErrNoSystemdSockets
+	}
+
+	if p, err := strconv.Atoi(pid); err != nil || p != ownPID {
+		return nil, fmt.Errorf("%w: sockets are passed to process %s", ErrNoSystemdSockets, pid)
+	}
+
+	count, err := strconv.Atoi(fds)
+	if err != nil || count < 1 {
+		return nil, fmt.Errorf("%w: invalid number of sockets %s", ErrNoSystemdSockets, fds)
+	}
+
+	fdNames := strings.Split(names, ":")
+	listeners := make([]NamedListener, 0, count)
+
+	for i := 0; i < count; i++ {
+		name := "LISTEN_FD_" + strconv.Itoa(start+i)
+		if i < len(fdNames) && fdNames[i] != "" {
+			name = fdNames[i]
+		}
+
+		file := os.NewFile(uintptr(start+i), name)
+
+		listener, err := net.FileListener(file)
+		_ = file.Close() // the listener uses a duplicate of the file descriptor
+
+		if err != nil {
+			for _, l := range listeners {
+				_ = l.Listener.Close()
+			}
+
+			return nil, fmt.Errorf("socket %s is no listener: %w", name, err)
+		}
+
+		listeners = append(listeners, NamedListener{Name: name, Listener: listener})
+	}
+
+	return listeners, nil
+}
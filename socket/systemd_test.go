@@ -0,0 +1,104 @@
+//go:build !windows
+// +build !windows
+
+package socket
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+func TestSystemdListeners(t *testing.T) { // nolint: funlen
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	defer func() {
+		_ = tcp.Close()
+	}()
+
+	file, err := tcp.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatalf("failed to get file of listener: %s", err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	// systemdListeners takes ownership of the file descriptor
+	fd, err := syscall.Dup(int(file.Fd()))
+	if err != nil {
+		t.Fatalf("failed to duplicate file descriptor: %s", err)
+	}
+	pid := strconv.Itoa(os.Getpid())
+
+	listeners, err := systemdListeners(pid, "1", "http", os.Getpid(), fd)
+	if err != nil {
+		t.Fatalf("expected no error but got: %s", err)
+	}
+
+	if len(listeners) != 1 || listeners[0].Name != "http" {
+		t.Fatalf("expected one listener named http but got %v", listeners)
+	}
+
+	if listeners[0].Listener.Addr().String() != tcp.Addr().String() {
+		t.Errorf("expected address %s but got %s", tcp.Addr(), listeners[0].Listener.Addr())
+	}
+
+	_ = listeners[0].Listener.Close()
+
+	regular, err := ioutil.TempFile("", "systemd")
+	if err != nil {
+		t.Fatalf("failed to create file: %s", err)
+	}
+
+	defer func() {
+		_ = regular.Close()
+		_ = os.Remove(regular.Name())
+	}()
+
+	testCases := []struct {
+		name    string
+		pid     string
+		fds     string
+		takesFD bool
+	}{
+		{name: "not activated"},
+		{name: "other process", pid: "1", fds: "1"},
+		{name: "invalid number", pid: pid, fds: "none"},
+		{name: "no socket", pid: pid, fds: "1", takesFD: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fd, err := syscall.Dup(int(regular.Fd()))
+			if err != nil {
+				t.Fatalf("failed to duplicate file descriptor: %s", err)
+			}
+
+			_, err = systemdListeners(testCase.pid, testCase.fds, "", os.Getpid(), fd)
+			if err == nil {
+				t.Error("expected an error but got nil")
+			}
+
+			if !testCase.takesFD {
+				_ = syscall.Close(fd)
+			}
+		})
+	}
+}
+
+func TestSystemd_NotActivated(t *testing.T) {
+	_ = os.Unsetenv(EnvListenPID)
+
+	if _, err := Systemd(); !errors.Is(err, ErrNoSystemdSockets) {
+		t.Errorf("expected error %v but got %v", ErrNoSystemdSockets, err)
+	}
+}
@@ -0,0 +1,64 @@
+package socket
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// ErrSocketInUse is returned if another process is accepting connections on the socket
+var ErrSocketInUse = errors.New("socket is in use")
+
+// dialTimeout is the time to wait for a connection when checking for a stale socket
+const dialTimeout = time.Second
+
+// Unix returns a listener on a Unix domain socket with the given permissions, e.g. 0660. A stale socket file left by
+// a process which didn't shut down cleanly is removed first. If another process still accepts connections on the
+// socket, ErrSocketInUse is returned. Other files are never removed. The socket file is removed when the listener is
+// closed.
+func Unix(path string, mode os.FileMode) (net.Listener, error) {
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+
+	if err := os.Chmod(path, mode); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("failed to set permissions of %s: %w", path, err)
+	}
+
+	return listener, nil
+}
+
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to check %s: %w", path, err)
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+
+	conn, err := net.DialTimeout("unix", path, dialTimeout)
+	if err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("%s: %w", path, ErrSocketInUse)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+	}
+
+	return nil
+}
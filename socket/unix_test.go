@@ -0,0 +1,92 @@
+package socket_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rebel-l/smis/socket"
+)
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "socket")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	return dir
+}
+
+func TestUnix(t *testing.T) {
+	path := filepath.Join(tempDir(t), "smis.sock")
+
+	listener, err := socket.Unix(path, 0660)
+	if err != nil {
+		t.Fatalf("expected no error but got: %s", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("expected socket file but got: %s", err)
+	}
+
+	if info.Mode().Perm() != 0660 {
+		t.Errorf("expected permissions %o but got %o", 0660, info.Mode().Perm())
+	}
+
+	if _, err = socket.Unix(path, 0660); !errors.Is(err, socket.ErrSocketInUse) {
+		t.Errorf("expected error %v but got %v", socket.ErrSocketInUse, err)
+	}
+
+	if err = listener.Close(); err != nil {
+		t.Fatalf("failed to close listener: %s", err)
+	}
+
+	if _, err = os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected socket file to be removed on close but got: %v", err)
+	}
+}
+
+func TestUnix_StaleSocket(t *testing.T) {
+	path := filepath.Join(tempDir(t), "smis.sock")
+
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = stale.Close()
+
+	listener, err := socket.Unix(path, 0600)
+	if err != nil {
+		t.Fatalf("expected stale socket to be replaced but got: %s", err)
+	}
+
+	_ = listener.Close()
+}
+
+func TestUnix_NoSocket(t *testing.T) {
+	path := filepath.Join(tempDir(t), "data.txt")
+
+	if err := ioutil.WriteFile(path, []byte("keep me"), 0600); err != nil {
+		t.Fatalf("failed to write file: %s", err)
+	}
+
+	if _, err := socket.Unix(path, 0600); err == nil {
+		t.Error("expected an error for existing file but got nil")
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected file to be kept but got: %s", err)
+	}
+}
@@ -1,10 +1,11 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/rebel-l/smis (interfaces: Server)
+// Source: github.com/rebel-l/smis (interfaces: Server,ShutdownServer)
 
 // Package smis_mock is a generated GoMock package.
 package smis_mock
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 )
@@ -45,3 +46,40 @@ func (mr *MockServerMockRecorder) ListenAndServe() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenAndServe", reflect.TypeOf((*MockServer)(nil).ListenAndServe))
 }
+
+// MockShutdownServer is a mock of ShutdownServer interface
+type MockShutdownServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockShutdownServerMockRecorder
+}
+
+// MockShutdownServerMockRecorder is the mock recorder for MockShutdownServer
+type MockShutdownServerMockRecorder struct {
+	mock *MockShutdownServer
+}
+
+// NewMockShutdownServer creates a new mock instance
+func NewMockShutdownServer(ctrl *gomock.Controller) *MockShutdownServer {
+	mock := &MockShutdownServer{ctrl: ctrl}
+	mock.recorder = &MockShutdownServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockShutdownServer) EXPECT() *MockShutdownServerMockRecorder {
+	return m.recorder
+}
+
+// Shutdown mocks base method
+func (m *MockShutdownServer) Shutdown(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Shutdown", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Shutdown indicates an expected call of Shutdown
+func (mr *MockShutdownServerMockRecorder) Shutdown(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockShutdownServer)(nil).Shutdown), arg0)
+}
@@ -0,0 +1,40 @@
+package smis
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/rebel-l/smis/middleware/clientcert"
+	"github.com/rebel-l/smis/tlsconfig"
+)
+
+// TLSServer is a server able to serve TLS, e.g. *http.Server.
+type TLSServer interface {
+	ListenAndServeTLS(certFile, keyFile string) error
+}
+
+// WithTLS configures the server to serve TLS. The certificate is reloaded when its files change, use TLSReloader to
+// check for reload errors. If the config contains a client CA file, the restricted chain demands a verified client
+// certificate and provides the client identity in the context, see clientcert.GetIdentity(). Call it after adding the
+// default middleware, so recovery and request ID run before the client certificate check. The server must be a
+// *http.Server.
+func (s *Service) WithTLS(config tlsconfig.Config) (*Service, error) {
+	server, ok := s.Server.(*http.Server)
+	if !ok {
+		return s, fmt.Errorf("TLS requires the server to be a *http.Server but it is %T", s.Server)
+	}
+
+	tlsConfig, reloader, err := config.Build()
+	if err != nil {
+		return s, fmt.Errorf("failed to configure TLS: %w", err)
+	}
+
+	server.TLSConfig = tlsConfig
+	s.TLSReloader = reloader
+
+	if config.ClientCAFile != "" {
+		s.AddMiddlewareForRestrictedChain(clientcert.New(s.Log))
+	}
+
+	return s, nil
+}
@@ -0,0 +1,212 @@
+package smis_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/rebel-l/smis"
+	"github.com/rebel-l/smis/tlsconfig"
+
+	"github.com/sirupsen/logrus"
+)
+
+// writeCertificateTest writes a self signed certificate and its key to dir and returns the file names.
+func writeCertificateTest(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %s", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to encode key: %s", err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+
+	err = ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	if err != nil {
+		t.Fatalf("failed to write certificate: %s", err)
+	}
+
+	err = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	if err != nil {
+		t.Fatalf("failed to write key: %s", err)
+	}
+
+	return certFile, keyFile
+}
+
+func TestService_WithTLS(t *testing.T) { // nolint: funlen
+	dir, err := ioutil.TempDir("", "smis")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	certFile, keyFile := writeCertificateTest(t, dir)
+
+	server := &http.Server{}
+
+	service, err := smis.NewService(server, mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	_, err = service.WithTLS(tlsconfig.Config{CertFile: certFile, KeyFile: keyFile, ClientCAFile: certFile})
+	if err != nil {
+		t.Fatalf("expected no error but got: %s", err)
+	}
+
+	if server.TLSConfig == nil || server.TLSConfig.GetCertificate == nil {
+		t.Fatal("expected TLS config with certificate of reloader")
+	}
+
+	if server.TLSConfig.ClientAuth != tls.VerifyClientCertIfGiven {
+		t.Errorf("expected client auth %s but got %s", tls.VerifyClientCertIfGiven, server.TLSConfig.ClientAuth)
+	}
+
+	if service.TLSReloader == nil {
+		t.Error("expected TLS reloader to be set")
+	}
+
+	handler := func(_ http.ResponseWriter, _ *http.Request) {}
+
+	if _, err = service.RegisterEndpointToPublicChain("/ping", http.MethodGet, handler); err != nil {
+		t.Fatalf("failed to register endpoint: %s", err)
+	}
+
+	if _, err = service.RegisterEndpointToRestictedChain("/ping", http.MethodGet, handler); err != nil {
+		t.Fatalf("failed to register endpoint: %s", err)
+	}
+
+	testCases := []struct {
+		path           string
+		expectedStatus int
+	}{
+		{path: "/public/ping", expectedStatus: http.StatusOK},
+		{path: "/restricted/ping", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			service.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, testCase.path, nil))
+
+			if testCase.expectedStatus != w.Code {
+				t.Errorf("expected status %d but got %d", testCase.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+type serverNoTLSTest struct{}
+
+func (serverNoTLSTest) ListenAndServe() error {
+	return nil
+}
+
+func (serverNoTLSTest) Shutdown(_ context.Context) error {
+	return nil
+}
+
+func TestService_WithTLS_Error(t *testing.T) {
+	service, err := smis.NewService(serverNoTLSTest{}, mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	if _, err = service.WithTLS(tlsconfig.Config{}); err == nil {
+		t.Error("expected an error for server without TLS support but got nil")
+	}
+
+	service.Server = &http.Server{}
+
+	if _, err = service.WithTLS(tlsconfig.Config{}); err == nil {
+		t.Error("expected an error for missing certificate but got nil")
+	}
+}
+
+type serverTLSTest struct {
+	serverNoTLSTest
+	certFile string
+	called   bool
+}
+
+func (s *serverTLSTest) ListenAndServeTLS(certFile, _ string) error {
+	s.called = true
+	s.certFile = certFile
+
+	return http.ErrServerClosed
+}
+
+func TestService_ListenAndServe_TLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "smis")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	certFile, keyFile := writeCertificateTest(t, dir)
+
+	reloader, err := tlsconfig.NewReloader(certFile, keyFile, 0)
+	if err != nil {
+		t.Fatalf("failed to create reloader: %s", err)
+	}
+
+	server := &serverTLSTest{}
+
+	service, err := smis.NewService(server, mux.NewRouter(), logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create service: %s", err)
+	}
+
+	service.TLSReloader = reloader
+
+	if err = service.ListenAndServe(); err != http.ErrServerClosed {
+		t.Errorf("expected error %v but got %v", http.ErrServerClosed, err)
+	}
+
+	if !server.called || server.certFile != "" {
+		t.Error("expected ListenAndServeTLS to be called without certificate files")
+	}
+}
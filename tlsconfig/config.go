@@ -0,0 +1,95 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+)
+
+// ErrNoCertificate is returned if the certificate or key file is missing in the config
+var ErrNoCertificate = errors.New("certificate and key file are required")
+
+// Config describes the TLS setup of a server. If ClientCAFile is set, client certificates are verified against the
+// CA bundle. ClientAuth defaults to tls.VerifyClientCertIfGiven then, so chains can decide whether they demand a
+// client certificate. MinVersion defaults to TLS 1.2, CipherSuites to DefaultCipherSuites(). CipherSuites are
+// ignored for TLS 1.3 as Go doesn't allow to configure them.
+type Config struct {
+	CertFile       string
+	KeyFile        string
+	ClientCAFile   string
+	ClientAuth     tls.ClientAuthType
+	MinVersion     uint16
+	CipherSuites   []uint16
+	ReloadInterval time.Duration
+}
+
+// DefaultCipherSuites returns the TLS 1.2 cipher suites providing forward secrecy and authenticated encryption.
+func DefaultCipherSuites() []uint16 {
+	return []uint16{
+		tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+		tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+		tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+		tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+		tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	}
+}
+
+// Build returns the tls.Config and the Reloader providing the certificate.
+func (c Config) Build() (*tls.Config, *Reloader, error) {
+	if c.CertFile == "" || c.KeyFile == "" {
+		return nil, nil, ErrNoCertificate
+	}
+
+	reloader, err := NewReloader(c.CertFile, c.KeyFile, c.ReloadInterval)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config := &tls.Config{
+		GetCertificate: reloader.GetCertificate,
+		MinVersion:     c.MinVersion,
+		CipherSuites:   c.CipherSuites,
+		ClientAuth:     c.ClientAuth,
+	}
+
+	if config.MinVersion == 0 {
+		config.MinVersion = tls.VersionTLS12
+	}
+
+	if len(config.CipherSuites) == 0 {
+		config.CipherSuites = DefaultCipherSuites()
+	}
+
+	if c.ClientCAFile != "" {
+		pool, err := loadCertPool(c.ClientCAFile)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		config.ClientCAs = pool
+
+		if config.ClientAuth == tls.NoClientCert {
+			config.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+
+	return config, reloader, nil
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("client CA file %s contains no certificates", file)
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,170 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestConfig_Build(t *testing.T) { // nolint: funlen
+	dir := tempDir(t)
+	server := newTestCertificate(t, dir, "server", nil)
+	ca := newTestCertificate(t, dir, "ca", nil)
+
+	emptyCA := filepath.Join(dir, "empty.pem")
+	if err := ioutil.WriteFile(emptyCA, []byte("no certificate"), 0600); err != nil {
+		t.Fatalf("failed to write file: %s", err)
+	}
+
+	testCases := []struct {
+		name               string
+		config             Config
+		expectedErr        bool
+		expectedMinVersion uint16
+		expectedCiphers    []uint16
+		expectedClientAuth tls.ClientAuthType
+	}{
+		{
+			name:               "defaults",
+			config:             Config{CertFile: server.certFile, KeyFile: server.keyFile},
+			expectedMinVersion: tls.VersionTLS12,
+			expectedCiphers:    DefaultCipherSuites(),
+			expectedClientAuth: tls.NoClientCert,
+		},
+		{
+			name: "custom",
+			config: Config{
+				CertFile:     server.certFile,
+				KeyFile:      server.keyFile,
+				ClientCAFile: ca.certFile,
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				MinVersion:   tls.VersionTLS13,
+				CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384},
+			},
+			expectedMinVersion: tls.VersionTLS13,
+			expectedCiphers:    []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384},
+			expectedClientAuth: tls.RequireAndVerifyClientCert,
+		},
+		{
+			name:               "client CA with default client auth",
+			config:             Config{CertFile: server.certFile, KeyFile: server.keyFile, ClientCAFile: ca.certFile},
+			expectedMinVersion: tls.VersionTLS12,
+			expectedCiphers:    DefaultCipherSuites(),
+			expectedClientAuth: tls.VerifyClientCertIfGiven,
+		},
+		{
+			name:        "missing certificate",
+			config:      Config{KeyFile: server.keyFile},
+			expectedErr: true,
+		},
+		{
+			name:        "missing client CA file",
+			config:      Config{CertFile: server.certFile, KeyFile: server.keyFile, ClientCAFile: dir + "/missing"},
+			expectedErr: true,
+		},
+		{
+			name:        "client CA file without certificates",
+			config:      Config{CertFile: server.certFile, KeyFile: server.keyFile, ClientCAFile: emptyCA},
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			config, reloader, err := testCase.config.Build()
+			if testCase.expectedErr {
+				if err == nil {
+					t.Error("expected an error but got nil")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %s", err)
+			}
+
+			if reloader == nil {
+				t.Error("expected a reloader but got nil")
+			}
+
+			if testCase.expectedMinVersion != config.MinVersion {
+				t.Errorf("expected min version %x but got %x", testCase.expectedMinVersion, config.MinVersion)
+			}
+
+			if !reflect.DeepEqual(testCase.expectedCiphers, config.CipherSuites) {
+				t.Errorf("expected cipher suites %v but got %v", testCase.expectedCiphers, config.CipherSuites)
+			}
+
+			if testCase.expectedClientAuth != config.ClientAuth {
+				t.Errorf("expected client auth %s but got %s", testCase.expectedClientAuth, config.ClientAuth)
+			}
+		})
+	}
+}
+
+func TestConfig_Build_MissingCertificate(t *testing.T) {
+	if _, _, err := (Config{}).Build(); !errors.Is(err, ErrNoCertificate) {
+		t.Errorf("expected error %v but got %v", ErrNoCertificate, err)
+	}
+}
+
+func TestConfig_Build_Handshake(t *testing.T) {
+	dir := tempDir(t)
+	ca := newTestCertificate(t, dir, "ca", nil)
+	server := newTestCertificate(t, dir, "server", ca)
+	client := newTestCertificate(t, dir, "client", ca)
+
+	config, _, err := Config{CertFile: server.certFile, KeyFile: server.keyFile, ClientCAFile: ca.certFile}.Build()
+	if err != nil {
+		t.Fatalf("failed to build config: %s", err)
+	}
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	defer func() {
+		_ = listener.Close()
+	}()
+
+	verified := make(chan [][]*x509.Certificate, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			verified <- nil
+			return
+		}
+
+		tlsConn := conn.(*tls.Conn)
+		_ = tlsConn.Handshake()
+		verified <- tlsConn.ConnectionState().VerifiedChains
+		_ = conn.Close()
+	}()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), &tls.Config{
+		RootCAs:      roots,
+		ServerName:   "localhost",
+		Certificates: []tls.Certificate{{Certificate: [][]byte{client.cert.Raw}, PrivateKey: client.key}},
+	})
+	if err != nil {
+		t.Fatalf("handshake failed: %s", err)
+	}
+
+	_ = conn.Handshake()
+	_ = conn.Close()
+
+	chains := <-verified
+	if len(chains) == 0 || chains[0][0].Subject.CommonName != "client" {
+		t.Errorf("expected verified client certificate but got %v", chains)
+	}
+}
@@ -0,0 +1,3 @@
+// Package tlsconfig builds TLS configurations for servers with certificates reloaded from disk when they change and
+// optional verification of client certificates.
+package tlsconfig
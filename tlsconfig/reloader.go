@@ -0,0 +1,135 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ReloadIntervalDefault is the default interval the certificate files are checked for changes
+const ReloadIntervalDefault = 10 * time.Second
+
+// Reloader provides the certificate of a server and reloads it as soon as the certificate or key file changes. The
+// files are checked at most once per interval during TLS handshakes. If reloading fails, the previous certificate is
+// kept and the error is returned by Err().
+type Reloader struct {
+	certFile    string
+	keyFile     string
+	interval    time.Duration
+	mutex       sync.RWMutex
+	certificate *tls.Certificate
+	modTime     time.Time
+	checked     time.Time
+	err         error
+	now         func() time.Time
+}
+
+// NewReloader loads the certificate and returns a Reloader. If interval is zero, ReloadIntervalDefault is used.
+func NewReloader(certFile, keyFile string, interval time.Duration) (*Reloader, error) {
+	if interval <= 0 {
+		interval = ReloadIntervalDefault
+	}
+
+	r := &Reloader{certFile: certFile, keyFile: keyFile, interval: interval, now: time.Now}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Reload loads the certificate from the files.
+func (r *Reloader) Reload() error {
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return r.setErr(err)
+	}
+
+	certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return r.setErr(fmt.Errorf("failed to load certificate: %w", err))
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.certificate = &certificate
+	r.modTime = modTime
+	r.checked = r.now()
+	r.err = nil
+
+	return nil
+}
+
+// GetCertificate returns the current certificate, it is used as tls.Config.GetCertificate.
+func (r *Reloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if r.needsCheck() {
+		r.checkFiles()
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.certificate, nil
+}
+
+// Err returns the error of the last failed reload or nil if the last reload succeeded.
+func (r *Reloader) Err() error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.err
+}
+
+func (r *Reloader) needsCheck() bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.now().Sub(r.checked) >= r.interval
+}
+
+func (r *Reloader) checkFiles() {
+	modTime, err := r.latestModTime()
+
+	r.mutex.Lock()
+	r.checked = r.now()
+	changed := err == nil && !modTime.Equal(r.modTime)
+	r.mutex.Unlock()
+
+	if err != nil {
+		_ = r.setErr(err)
+		return
+	}
+
+	if changed {
+		_ = r.Reload()
+	}
+}
+
+func (r *Reloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+
+	for _, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return latest, fmt.Errorf("failed to check certificate file: %w", err)
+		}
+
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
+
+func (r *Reloader) setErr(err error) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.err = err
+
+	return err
+}
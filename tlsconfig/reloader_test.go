@@ -0,0 +1,80 @@
+package tlsconfig
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestReloader(t *testing.T) { // nolint: funlen
+	dir := tempDir(t)
+	first := newTestCertificate(t, dir, "server", nil)
+
+	reloader, err := NewReloader(first.certFile, first.keyFile, time.Minute)
+	if err != nil {
+		t.Fatalf("failed to create reloader: %s", err)
+	}
+
+	now := time.Now()
+	reloader.now = func() time.Time {
+		return now
+	}
+
+	assertCertificate := func(t *testing.T, expected *testCertificate) {
+		t.Helper()
+
+		certificate, err := reloader.GetCertificate(nil)
+		if err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+
+		if !bytes.Equal(expected.cert.Raw, certificate.Certificate[0]) {
+			t.Errorf("expected certificate with serial %s", expected.cert.SerialNumber)
+		}
+	}
+
+	assertCertificate(t, first)
+
+	// files are replaced, but the interval isn't over
+	second := newTestCertificate(t, dir, "server", nil)
+	later := time.Now().Add(time.Hour)
+
+	for _, file := range []string{second.certFile, second.keyFile} {
+		if err := os.Chtimes(file, later, later); err != nil {
+			t.Fatalf("failed to change modification time: %s", err)
+		}
+	}
+
+	assertCertificate(t, first)
+
+	// interval is over
+	now = now.Add(time.Minute)
+
+	assertCertificate(t, second)
+
+	if reloader.Err() != nil {
+		t.Errorf("expected no reload error but got: %s", reloader.Err())
+	}
+
+	// missing files keep the previous certificate
+	if err := os.Remove(second.keyFile); err != nil {
+		t.Fatalf("failed to remove key file: %s", err)
+	}
+
+	now = now.Add(time.Minute)
+
+	assertCertificate(t, second)
+
+	if reloader.Err() == nil {
+		t.Error("expected reload error but got nil")
+	}
+}
+
+func TestNewReloader_Error(t *testing.T) {
+	dir := tempDir(t)
+
+	if _, err := NewReloader(dir+"/missing.crt", dir+"/missing.key", 0); err == nil {
+		t.Error("expected an error for missing files but got nil")
+	}
+}
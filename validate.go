@@ -0,0 +1,314 @@
+package smis
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// TagValidate is the struct tag containing the validation rules
+	TagValidate = "validate"
+
+	// RuleRequired demands a non zero value
+	RuleRequired = "required"
+
+	// RuleMin demands a minimum value for numbers or minimum length for strings, slices and maps
+	RuleMin = "min"
+
+	// RuleMax demands a maximum value for numbers or maximum length for strings, slices and maps
+	RuleMax = "max"
+
+	// RuleRegex demands a string to match the regular expression, it must be the last rule of the tag
+	RuleRegex = "regex"
+
+	// RuleEnum demands the value to be one of the values separated by |
+	RuleEnum = "enum"
+)
+
+// nolint: gochecknoglobals
+var regexCache sync.Map
+
+// FieldError describes why a field is invalid. Field is the path to the field using the JSON or parameter names.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidateStruct checks the fields of a struct against the rules in their validate tags, e.g.
+// `validate:"required,min=3,max=20,regex=^[a-z]+$"` or `validate:"enum=draft|published"`. Nested structs, pointers
+// and slices of structs are validated too. It returns all invalid fields. An error is returned if a tag is invalid.
+func ValidateStruct(v interface{}) ([]FieldError, error) {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil, nil
+		}
+
+		value = value.Elem()
+	}
+
+	var fields []FieldError
+
+	if err := validateValue(value, "", &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+func validateValue(value reflect.Value, path string, fields *[]FieldError) error {
+	switch value.Kind() { // nolint: exhaustive
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+
+		return validateValue(value.Elem(), path, fields)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := validateValue(value.Index(i), fmt.Sprintf("%s[%d]", path, i), fields); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		return validateStruct(value, path, fields)
+	}
+
+	return nil
+}
+
+func validateStruct(value reflect.Value, path string, fields *[]FieldError) error {
+	t := value.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			// fields of embedded structs are promoted like encoding/json does
+			if err := validateStruct(value.Field(i), path, fields); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+
+		name := fieldName(field)
+		if name == "-" {
+			continue
+		}
+
+		if path != "" {
+			name = path + "." + name
+		}
+
+		fieldValue := value.Field(i)
+
+		if tag, ok := field.Tag.Lookup(TagValidate); ok {
+			fieldErr, err := validateField(fieldValue, name, tag)
+			if err != nil {
+				return err
+			}
+
+			if fieldErr != nil {
+				*fields = append(*fields, *fieldErr)
+				continue
+			}
+		}
+
+		if err := validateValue(fieldValue, name, fields); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateField checks the rules of the tag in order and returns the first violation.
+func validateField(value reflect.Value, name, tag string) (*FieldError, error) {
+	rules := splitRules(tag)
+
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			if rules[0].name == RuleRequired {
+				return &FieldError{Field: name, Rule: RuleRequired, Message: "is required"}, nil
+			}
+
+			return nil, nil
+		}
+
+		value = value.Elem()
+	}
+
+	for _, r := range rules {
+		msg, err := r.check(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule '%s' for field %s: %w", r.name, name, err)
+		}
+
+		if msg != "" {
+			return &FieldError{Field: name, Rule: r.name, Message: msg}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+type rule struct {
+	name  string
+	param string
+}
+
+// splitRules splits the tag by comma. As regular expressions may contain commas, the regex rule takes the rest of
+// the tag. The required rule is moved to the front.
+func splitRules(tag string) []rule {
+	var rules []rule
+
+	for tag != "" {
+		var part string
+		if strings.HasPrefix(tag, RuleRegex+"=") {
+			part, tag = tag, ""
+		} else {
+			parts := strings.SplitN(tag, ",", 2)
+			part = parts[0]
+			tag = ""
+
+			if len(parts) == 2 {
+				tag = parts[1]
+			}
+		}
+
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		r := rule{name: kv[0]}
+
+		if len(kv) == 2 {
+			r.param = kv[1]
+		}
+
+		if r.name == RuleRequired {
+			rules = append([]rule{r}, rules...)
+		} else if r.name != "" {
+			rules = append(rules, r)
+		}
+	}
+
+	if len(rules) == 0 {
+		rules = append(rules, rule{})
+	}
+
+	return rules
+}
+
+func (r rule) check(value reflect.Value) (string, error) {
+	switch r.name {
+	case "":
+		return "", nil
+	case RuleRequired:
+		if value.IsZero() {
+			return "is required", nil
+		}
+	case RuleMin, RuleMax:
+		return r.checkRange(value)
+	case RuleRegex:
+		return r.checkRegex(value)
+	case RuleEnum:
+		actual := fmt.Sprint(value.Interface())
+		for _, allowed := range strings.Split(r.param, "|") {
+			if actual == allowed {
+				return "", nil
+			}
+		}
+
+		return fmt.Sprintf("must be one of %s", strings.ReplaceAll(r.param, "|", ", ")), nil
+	default:
+		return "", fmt.Errorf("unknown rule")
+	}
+
+	return "", nil
+}
+
+func (r rule) checkRange(value reflect.Value) (string, error) {
+	limit, err := strconv.ParseFloat(r.param, 64)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		actual float64
+		unit   string
+	)
+
+	switch value.Kind() { // nolint: exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		actual = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		actual = value.Float()
+	case reflect.String:
+		actual = float64(len([]rune(value.String())))
+		unit = "length "
+	case reflect.Slice, reflect.Array, reflect.Map:
+		actual = float64(value.Len())
+		unit = "number of items "
+	default:
+		return "", fmt.Errorf("not supported for %s", value.Kind())
+	}
+
+	if r.name == RuleMin && actual < limit {
+		return fmt.Sprintf("%smust be at least %s", unit, r.param), nil
+	}
+
+	if r.name == RuleMax && actual > limit {
+		return fmt.Sprintf("%smust be at most %s", unit, r.param), nil
+	}
+
+	return "", nil
+}
+
+func (r rule) checkRegex(value reflect.Value) (string, error) {
+	if value.Kind() != reflect.String {
+		return "", fmt.Errorf("not supported for %s", value.Kind())
+	}
+
+	var re *regexp.Regexp
+
+	if cached, ok := regexCache.Load(r.param); ok {
+		re = cached.(*regexp.Regexp)
+	} else {
+		var err error
+		if re, err = regexp.Compile(r.param); err != nil {
+			return "", err
+		}
+
+		regexCache.Store(r.param, re)
+	}
+
+	if !re.MatchString(value.String()) {
+		return fmt.Sprintf("must match %s", r.param), nil
+	}
+
+	return "", nil
+}
+
+// fieldName returns the JSON name of the field or the name of the parameter it is bound to, see Request.Bind().
+func fieldName(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" {
+		return name
+	}
+
+	for _, source := range parameterSources {
+		if name := field.Tag.Get(source.tag); name != "" {
+			return name
+		}
+	}
+
+	return field.Name
+}
@@ -0,0 +1,135 @@
+package smis_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rebel-l/smis"
+)
+
+type addressTest struct {
+	City string `json:"city" validate:"required"`
+}
+
+type userTest struct {
+	Name     string         `json:"name" validate:"required,min=3,max=10"`
+	Login    string         `json:"login" validate:"regex=^[a-z]{2,}(,[a-z]+)?$"`
+	Age      int            `json:"age" validate:"min=18,max=130"`
+	Status   string         `json:"status" validate:"enum=draft|published"`
+	Tags     []string       `json:"tags" validate:"max=2"`
+	Nickname *string        `json:"nickname" validate:"min=2"`
+	Email    *string        `json:"email" validate:"required"`
+	Address  *addressTest   `json:"address"`
+	Previous []addressTest  `json:"previous"`
+	internal string         `validate:"required"`
+	Ignored  string         `json:"-" validate:"required"`
+	Extra    map[string]int `validate:"min=1"`
+}
+
+func TestValidateStruct(t *testing.T) { // nolint: funlen
+	email := "a@example.com"
+	short := "x"
+
+	testCases := []struct {
+		name     string
+		value    interface{}
+		expected []smis.FieldError
+	}{
+		{
+			name: "valid",
+			value: &userTest{
+				Name:     "Herbert",
+				Login:    "herbert,h",
+				Age:      42,
+				Status:   "draft",
+				Tags:     []string{"a"},
+				Email:    &email,
+				Address:  &addressTest{City: "Berlin"},
+				Previous: []addressTest{{City: "Hamburg"}},
+				Extra:    map[string]int{"a": 1},
+			},
+		},
+		{
+			name: "invalid",
+			value: userTest{
+				Name:     "He",
+				Login:    "Herbert",
+				Age:      12,
+				Status:   "deleted",
+				Tags:     []string{"a", "b", "c"},
+				Nickname: &short,
+				Address:  &addressTest{},
+				Previous: []addressTest{{City: "Hamburg"}, {}},
+			},
+			expected: []smis.FieldError{
+				{Field: "name", Rule: "min", Message: "length must be at least 3"},
+				{Field: "login", Rule: "regex", Message: "must match ^[a-z]{2,}(,[a-z]+)?$"},
+				{Field: "age", Rule: "min", Message: "must be at least 18"},
+				{Field: "status", Rule: "enum", Message: "must be one of draft, published"},
+				{Field: "tags", Rule: "max", Message: "number of items must be at most 2"},
+				{Field: "nickname", Rule: "min", Message: "length must be at least 2"},
+				{Field: "email", Rule: "required", Message: "is required"},
+				{Field: "address.city", Rule: "required", Message: "is required"},
+				{Field: "previous[1].city", Rule: "required", Message: "is required"},
+				{Field: "Extra", Rule: "min", Message: "number of items must be at least 1"},
+			},
+		},
+		{
+			name:  "nil",
+			value: (*userTest)(nil),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := smis.ValidateStruct(testCase.value)
+			if err != nil {
+				t.Fatalf("expected no error but got: %s", err)
+			}
+
+			if !reflect.DeepEqual(testCase.expected, got) {
+				t.Errorf("expected field errors\n%v\nbut got\n%v", testCase.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidateStruct_InvalidTag(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value interface{}
+	}{
+		{
+			name: "unknown rule",
+			value: struct {
+				Name string `validate:"uppercase"`
+			}{},
+		},
+		{
+			name: "invalid min",
+			value: struct {
+				Name string `validate:"min=three"`
+			}{},
+		},
+		{
+			name: "invalid regex",
+			value: struct {
+				Name string `validate:"regex=[a-"`
+			}{},
+		},
+		{
+			name: "regex for number",
+			value: struct {
+				Age int `validate:"regex=^[0-9]+$"`
+			}{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if _, err := smis.ValidateStruct(testCase.value); err == nil {
+				t.Error("expected an error for invalid tag but got nil")
+			}
+		})
+	}
+}